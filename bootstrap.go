@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"service-operation/config"
+	"service-operation/monitoring"
+	"service-operation/pocketbase"
+	"service-operation/sinks"
+	"service-operation/statecache"
+	"service-operation/uptime"
+)
+
+const (
+	bootstrapMinBackoff = 5 * time.Second
+	bootstrapMaxBackoff = 5 * time.Minute
+)
+
+// agentBootstrap connects to PocketBase and registers the regional agent, retrying with
+// exponential backoff until it succeeds, and then starts monitoring
+type agentBootstrap struct {
+	cfg            *config.Config
+	pbClient       *pocketbase.PocketBaseClient
+	regionalConfig *config.RegionalConfigManager
+	cache          *statecache.Cache
+	uptime         *uptime.Tracker
+
+	mu                sync.Mutex
+	registered        bool
+	lastError         string
+	monitoringService *monitoring.MonitoringService
+	stopChan          chan struct{}
+}
+
+func newAgentBootstrap(cfg *config.Config, pbClient *pocketbase.PocketBaseClient, tracker *uptime.Tracker) *agentBootstrap {
+	return &agentBootstrap{
+		cfg:            cfg,
+		pbClient:       pbClient,
+		regionalConfig: config.NewRegionalConfigManager(cfg, pbClient),
+		cache:          statecache.New(cfg.StateDir),
+		uptime:         tracker,
+		lastError:      "bootstrap not started",
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// run retries the bootstrap until it succeeds, fails permanently or the agent stops
+func (b *agentBootstrap) run() {
+	// Missing REGION_NAME or AGENT_ID will not fix itself, so there is nothing to retry
+	if err := b.regionalConfig.ValidateRegionalConfig(); err != nil {
+		log.Printf("Error: Invalid regional configuration: %v", err)
+		log.Printf("Please set REGION_NAME and AGENT_ID environment variables")
+		log.Printf("Monitoring will not start without valid regional configuration")
+		b.setError(fmt.Sprintf("invalid regional configuration: %v", err))
+		return
+	}
+
+	backoff := bootstrapMinBackoff
+	for attempt := 1; ; attempt++ {
+		err := b.attempt()
+		if err == nil {
+			return
+		}
+
+		b.setError(err.Error())
+		log.Printf("⚠️  Bootstrap attempt %d failed, retrying in %v: %v", attempt, backoff, err)
+
+		select {
+		case <-b.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > bootstrapMaxBackoff {
+			backoff = bootstrapMaxBackoff
+		}
+	}
+}
+
+// attempt connects to the backend, registers the agent and starts monitoring
+func (b *agentBootstrap) attempt() error {
+	if err := b.pbClient.TestConnection(); err != nil {
+		b.startFromCache()
+		return fmt.Errorf("PocketBase connection test failed: %v", err)
+	}
+	go b.pbClient.ReplaySpool()
+
+	regionalService, err := b.regionalConfig.RegisterRegionalService()
+	if err != nil {
+		b.startFromCache()
+		return fmt.Errorf("regional registration failed: %v", err)
+	}
+
+	// Final validation - ensure all required fields are present
+	if regionalService.RegionName == "" || regionalService.AgentID == "" {
+		return fmt.Errorf("invalid regional service configuration - region_name: '%s', agent_id: '%s'",
+			regionalService.RegionName, regionalService.AgentID)
+	}
+
+	if err := b.cache.SaveRegionalService(regionalService); err != nil {
+		log.Printf("⚠️  Failed to cache regional service: %v", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.monitoringService != nil {
+		running, _ := b.monitoringService.GetRegionalInfo()
+		if running == regionalService.RegionName {
+			// Already monitoring from the cached registration; it reconnects on its own
+			b.registered = true
+			b.lastError = ""
+			log.Printf("🎯 Registered with backend, continuing monitoring: Region=%s, Agent=%s", regionalService.RegionName, regionalService.AgentID)
+			return nil
+		}
+
+		log.Printf("🔄 Region changed from cached %s to %s, restarting monitoring", running, regionalService.RegionName)
+		b.stopMonitoring()
+	}
+
+	if err := b.startMonitoring(regionalService); err != nil {
+		return err
+	}
+	b.registered = true
+	b.lastError = ""
+
+	log.Printf("🎯 Regional monitoring active: Region=%s, Agent=%s", regionalService.RegionName, regionalService.AgentID)
+	return nil
+}
+
+// startFromCache starts monitoring with the cached registration when the backend is
+// unreachable, so a restart during an outage keeps probing the last known assignments.
+// Results are spooled until the backend is back.
+func (b *agentBootstrap) startFromCache() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.monitoringService != nil {
+		return
+	}
+
+	regionalService, err := b.cache.LoadRegionalService()
+	if err != nil {
+		return // Never registered on this host
+	}
+	if regionalService.RegionName == "" || regionalService.AgentID != b.cfg.AgentID {
+		return
+	}
+	if regionalService.Token != "" {
+		b.pbClient.SetAgentToken(regionalService.AgentID, regionalService.Token)
+	}
+
+	if err := b.startMonitoring(regionalService); err != nil {
+		log.Printf("⚠️  Failed to start monitoring from cache: %v", err)
+		return
+	}
+	log.Printf("📦 Backend unreachable, monitoring from cached registration: Region=%s, Agent=%s", regionalService.RegionName, regionalService.AgentID)
+}
+
+// startMonitoring creates and starts the monitoring service. The caller must hold b.mu.
+func (b *agentBootstrap) startMonitoring(regionalService *pocketbase.RegionalService) error {
+	select {
+	case <-b.stopChan:
+		return nil // Agent is shutting down
+	default:
+	}
+
+	// Fan results out to PocketBase and any other configured sinks
+	resultSink, err := sinks.NewFromConfig(b.cfg, b.pbClient)
+	if err != nil {
+		return fmt.Errorf("failed to set up result sinks: %v", err)
+	}
+
+	// Initialize and start monitoring service with regional support
+	b.monitoringService = monitoring.NewMonitoringServiceWithRegional(b.cfg, b.pbClient, regionalService)
+	b.monitoringService.SetResultSink(resultSink)
+	b.monitoringService.SetAssignmentCache(b.cache)
+	b.monitoringService.SetUptimeTracker(b.uptime)
+	go b.monitoringService.Start()
+	return nil
+}
+
+// stopMonitoring shuts the monitoring service down gracefully, waiting for its running checks
+// and flushing its sinks. The caller must hold b.mu; it is released while waiting so that
+// health checks are not blocked.
+func (b *agentBootstrap) stopMonitoring() {
+	monitoringService := b.monitoringService
+	if monitoringService == nil {
+		return
+	}
+	b.monitoringService = nil
+	b.mu.Unlock()
+	defer b.mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.cfg.ShutdownTimeout)
+	defer cancel()
+	monitoringService.Shutdown(ctx)
+}
+
+func (b *agentBootstrap) setError(message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastError = message
+}
+
+// Registered reports whether bootstrap completed and, if not, why
+func (b *agentBootstrap) Registered() (bool, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.registered && b.monitoringService != nil {
+		return false, "monitoring from cached assignments: " + b.lastError
+	}
+	return b.registered, b.lastError
+}
+
+// shutdown ends the retry loop and shuts monitoring down gracefully if it was started
+func (b *agentBootstrap) shutdown(ctx context.Context) {
+	b.mu.Lock()
+	select {
+	case <-b.stopChan:
+		b.mu.Unlock()
+		return
+	default:
+		close(b.stopChan)
+	}
+
+	// The monitoring service closes its result sink on shutdown
+	monitoringService := b.monitoringService
+	b.monitoringService = nil
+	b.mu.Unlock()
+
+	if monitoringService != nil {
+		monitoringService.Shutdown(ctx)
+	}
+}
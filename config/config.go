@@ -13,28 +13,69 @@ import (
 type Config struct {
 	// Server configuration
 	Port string
-	
+
 	// Operation configuration (needed for handling operations)
 	DefaultCount   int
 	DefaultTimeout time.Duration
 	MaxCount       int
 	MaxTimeout     time.Duration
 	EnableLogging  bool
-	
+
 	// PocketBase configuration
 	PocketBaseEnabled bool
 	PocketBaseURL     string
-	
+
+	// PocketBase authentication as an auth record (optional)
+	PocketBaseAuthCollection string
+	PocketBaseIdentity       string
+	PocketBasePassword       string
+
 	// Regional Agent configuration
-	RegionName      string
-	AgentID         string
-	AgentIPAddress  string
-	Token           string
-	
+	RegionName     string
+	AgentID        string
+	AgentIPAddress string
+	Token          string
+
 	// Monitoring configuration
-	CheckInterval   time.Duration
-	MaxRetries      int
-	RequestTimeout  time.Duration
+	CheckInterval  time.Duration
+	MaxRetries     int
+	RequestTimeout time.Duration
+
+	// Check scheduling
+	MaxConcurrentChecks int           // Worker pool size shared by all monitors
+	CheckJitter         time.Duration // Maximum random offset of each run
+	CheckStartSpread    time.Duration // Window over which first runs are spread
+
+	// Retry and status confirmation
+	RetryDelay        time.Duration
+	FailureThreshold  int // Consecutive failed checks before a service is marked down
+	RecoveryThreshold int // Consecutive successful checks before a down service is marked up
+
+	// Time allowed for draining checks and flushing results on shutdown
+	ShutdownTimeout time.Duration
+
+	// Local state and offline spool
+	StateDir       string
+	SpoolEnabled   bool
+	SpoolMaxMB     int
+	SpoolSegmentMB int
+
+	// Blackbox-style /probe modules file (YAML or JSON)
+	ProbeModulesFile string
+
+	// Standalone mode: services from a local file instead of PocketBase
+	ServicesFile  string
+	ResultsOutput string // JSONL file path, or "stdout"; defaults to stdout in standalone mode
+
+	// Result sinks, each with its own queue and retries
+	SinkQueueSize      int
+	SinkMaxRetries     int
+	InfluxURL          string // Line protocol write endpoint
+	InfluxToken        string
+	StatsDAddr         string // host:port, UDP
+	StatsDPrefix       string
+	WebhookURL         string
+	WebhookChangesOnly bool
 }
 
 func Load() *Config {
@@ -47,21 +88,46 @@ func Load() *Config {
 	}
 
 	return &Config{
-		Port:              getEnv("PORT", "8091"),
-		DefaultCount:      getIntEnv("DEFAULT_COUNT", 4),
-		DefaultTimeout:    getDurationEnv("DEFAULT_TIMEOUT", 10*time.Second),
-		MaxCount:          getIntEnv("MAX_COUNT", 20),
-		MaxTimeout:        getDurationEnv("MAX_TIMEOUT", 30*time.Second),
-		EnableLogging:     getBoolEnv("ENABLE_LOGGING", true),
-		PocketBaseEnabled: getBoolEnv("POCKETBASE_ENABLED", true),
-		PocketBaseURL:     getEnv("POCKETBASE_URL", "http://localhost:8090"),
-		RegionName:        getEnv("REGION_NAME", ""),        // No default - must be set
-		AgentID:           getEnv("AGENT_ID", ""),           // No default - must be set
-		AgentIPAddress:    getEnv("AGENT_IP_ADDRESS", ""),   // No default - must be set
-		Token:             getEnv("AGENT_TOKEN", ""),
-		CheckInterval:     getDurationEnv("CHECK_INTERVAL", 30*time.Second),
-		MaxRetries:        getIntEnv("MAX_RETRIES", 3),
-		RequestTimeout:    getDurationEnv("REQUEST_TIMEOUT", 10*time.Second),
+		Port:                     getEnv("PORT", "8091"),
+		DefaultCount:             getIntEnv("DEFAULT_COUNT", 4),
+		DefaultTimeout:           getDurationEnv("DEFAULT_TIMEOUT", 10*time.Second),
+		MaxCount:                 getIntEnv("MAX_COUNT", 20),
+		MaxTimeout:               getDurationEnv("MAX_TIMEOUT", 30*time.Second),
+		EnableLogging:            getBoolEnv("ENABLE_LOGGING", true),
+		PocketBaseEnabled:        getBoolEnv("POCKETBASE_ENABLED", true),
+		PocketBaseURL:            getEnv("POCKETBASE_URL", "http://localhost:8090"),
+		PocketBaseAuthCollection: getEnv("POCKETBASE_AUTH_COLLECTION", "users"),
+		PocketBaseIdentity:       getEnv("POCKETBASE_IDENTITY", ""),
+		PocketBasePassword:       getEnv("POCKETBASE_PASSWORD", ""),
+		RegionName:               getEnv("REGION_NAME", ""),      // No default - must be set
+		AgentID:                  getEnv("AGENT_ID", ""),         // No default - must be set
+		AgentIPAddress:           getEnv("AGENT_IP_ADDRESS", ""), // No default - must be set
+		Token:                    getEnv("AGENT_TOKEN", ""),
+		CheckInterval:            getDurationEnv("CHECK_INTERVAL", 30*time.Second),
+		MaxRetries:               getIntEnv("MAX_RETRIES", 3),
+		RequestTimeout:           getDurationEnv("REQUEST_TIMEOUT", 10*time.Second),
+		MaxConcurrentChecks:      getIntEnv("MAX_CONCURRENT_CHECKS", 20),
+		CheckJitter:              getDurationEnv("CHECK_JITTER", 5*time.Second),
+		CheckStartSpread:         getDurationEnv("CHECK_START_SPREAD", 30*time.Second),
+		RetryDelay:               getDurationEnv("RETRY_DELAY", 2*time.Second),
+		FailureThreshold:         getIntEnv("FAILURE_THRESHOLD", 2),
+		RecoveryThreshold:        getIntEnv("RECOVERY_THRESHOLD", 1),
+		ShutdownTimeout:          getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+		StateDir:                 getEnv("STATE_DIR", "/var/lib/regional-check-agent"),
+		SpoolEnabled:             getBoolEnv("SPOOL_ENABLED", true),
+		SpoolMaxMB:               getIntEnv("SPOOL_MAX_MB", 100),
+		SpoolSegmentMB:           getIntEnv("SPOOL_SEGMENT_MB", 4),
+		ProbeModulesFile:         getEnv("PROBE_MODULES_FILE", ""),
+		ServicesFile:             getEnv("SERVICES_FILE", ""),
+		ResultsOutput:            getEnv("RESULTS_OUTPUT", ""),
+		SinkQueueSize:            getIntEnv("SINK_QUEUE_SIZE", 1000),
+		SinkMaxRetries:           getIntEnv("SINK_MAX_RETRIES", 3),
+		InfluxURL:                getEnv("INFLUX_URL", ""),
+		InfluxToken:              getEnv("INFLUX_TOKEN", ""),
+		StatsDAddr:               getEnv("STATSD_ADDR", ""),
+		StatsDPrefix:             getEnv("STATSD_PREFIX", "regional_check"),
+		WebhookURL:               getEnv("WEBHOOK_URL", ""),
+		WebhookChangesOnly:       getBoolEnv("WEBHOOK_CHANGES_ONLY", false),
 	}
 }
 
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAMLFile decodes YAML or JSON data into out through its JSON tags, so local files use
+// the same field names as the API and PocketBase records
+func decodeYAMLFile(data []byte, out interface{}) error {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, out)
+}
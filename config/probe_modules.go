@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"service-operation/operations"
+	"service-operation/types"
+)
+
+// DefaultProbeModules are available on /probe without a modules file. Module names follow
+// blackbox_exporter's example configuration.
+func DefaultProbeModules() map[string]types.OperationRequest {
+	return map[string]types.OperationRequest{
+		"http_2xx":      {Type: types.OperationHTTP, Method: "GET", Assertions: &types.HTTPAssertions{StatusCodes: "2xx"}},
+		"http_post_2xx": {Type: types.OperationHTTP, Method: "POST", Assertions: &types.HTTPAssertions{StatusCodes: "2xx"}},
+		"tcp_connect":   {Type: types.OperationTCP},
+		"icmp":          {Type: types.OperationPing, Count: 1},
+		"dns":           {Type: types.OperationDNS, Query: "A"},
+		"dns-zone":      {Type: operations.OperationDNSZone},
+		"tls":           {Type: types.OperationTLS},
+	}
+}
+
+// LoadProbeModules reads /probe modules from a YAML or JSON file of the form
+//
+//	modules:
+//	  http_2xx:
+//	    type: http
+//	    timeout: 5
+//	    assertions:
+//	      status_codes: 2xx
+//
+// Module fields are the same as /operation request fields. The built-in modules are
+// included unless the file redefines them.
+func LoadProbeModules(path string) (map[string]types.OperationRequest, error) {
+	modules := DefaultProbeModules()
+	if path == "" {
+		return modules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return modules, err
+	}
+
+	var file struct {
+		Modules map[string]types.OperationRequest `json:"modules"`
+	}
+	if err := decodeYAMLFile(data, &file); err != nil {
+		return modules, fmt.Errorf("invalid probe modules file %s: %v", path, err)
+	}
+
+	for name, module := range file.Modules {
+		if module.Type == "" {
+			return modules, fmt.Errorf("probe module %s: type is required", name)
+		}
+		modules[name] = module
+	}
+
+	return modules, nil
+}
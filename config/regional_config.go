@@ -23,11 +23,21 @@ func NewRegionalConfigManager(config *Config, pbClient *pocketbase.PocketBaseCli
 }
 
 func (rcm *RegionalConfigManager) LoadOrCreateRegionalService() (*pocketbase.RegionalService, error) {
+	service, err := rcm.RegisterRegionalService()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return rcm.createFallbackService(), nil
+	}
+	return service, nil
+}
+
+// RegisterRegionalService loads the regional service of this agent, creating it if needed.
+// Unlike LoadOrCreateRegionalService it reports backend failures instead of falling back.
+func (rcm *RegionalConfigManager) RegisterRegionalService() (*pocketbase.RegionalService, error) {
 	// First try to find existing service by agent_id
 	services, err := rcm.pbClient.GetRegionalServices()
 	if err != nil {
-		log.Printf("Warning: Could not get regional services: %v", err)
-		return rcm.createFallbackService(), nil
+		return nil, fmt.Errorf("could not get regional services: %v", err)
 	}
 
 	// Look for existing service with matching agent_id
@@ -65,23 +75,23 @@ func (rcm *RegionalConfigManager) createNewRegionalService() (*pocketbase.Region
 
 	err := rcm.pbClient.CreateRecord("regional_service", serviceData)
 	if err != nil {
-		log.Printf("Warning: Could not create regional service: %v", err)
-		return rcm.createFallbackService(), nil
+		return nil, fmt.Errorf("could not create regional service: %v", err)
 	}
 
 	// Fetch the created service
 	services, err := rcm.pbClient.GetRegionalServices()
-	if err == nil {
-		for _, service := range services {
-			if service.AgentID == rcm.config.AgentID {
-				rcm.service = &service
-				rcm.updateConfigFromService(&service)
-				return &service, nil
-			}
+	if err != nil {
+		return nil, fmt.Errorf("could not get created regional service: %v", err)
+	}
+	for _, service := range services {
+		if service.AgentID == rcm.config.AgentID {
+			rcm.service = &service
+			rcm.updateConfigFromService(&service)
+			return &service, nil
 		}
 	}
 
-	return rcm.createFallbackService(), nil
+	return nil, fmt.Errorf("created regional service for agent %s was not found", rcm.config.AgentID)
 }
 
 func (rcm *RegionalConfigManager) createFallbackService() *pocketbase.RegionalService {
@@ -112,6 +122,9 @@ func (rcm *RegionalConfigManager) updateConfigFromService(service *pocketbase.Re
 	if service.Token != "" {
 		rcm.config.Token = service.Token
 	}
+
+	// Authenticate with the token stored in regional_service from now on
+	rcm.pbClient.SetAgentToken(rcm.config.AgentID, rcm.config.Token)
 }
 
 func (rcm *RegionalConfigManager) GetRegionalInfo() (string, string) {
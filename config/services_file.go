@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"service-operation/pocketbase"
+)
+
+// LoadServicesFile reads the services monitored in standalone mode from a YAML or JSON file.
+// Entries use the fields of the PocketBase services collection. The file is either a list of
+// services or an object with a "services" list.
+func LoadServicesFile(path string) ([]pocketbase.Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Services []pocketbase.Service `json:"services"`
+	}
+	if err := decodeYAMLFile(data, &file); err != nil {
+		// Not an object, try a plain list of services
+		if listErr := decodeYAMLFile(data, &file.Services); listErr != nil {
+			return nil, fmt.Errorf("invalid services file %s: %v", path, err)
+		}
+	}
+	services := file.Services
+
+	seen := make(map[string]bool)
+	for i := range services {
+		service := &services[i]
+		if service.ID == "" {
+			service.ID = service.Name
+		}
+		if service.ID == "" {
+			return nil, fmt.Errorf("services file %s: entry %d needs an id or name", path, i+1)
+		}
+		if service.Name == "" {
+			service.Name = service.ID
+		}
+		if seen[service.ID] {
+			return nil, fmt.Errorf("services file %s: duplicate service id %q", path, service.ID)
+		}
+		seen[service.ID] = true
+	}
+
+	return services, nil
+}
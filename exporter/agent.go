@@ -0,0 +1,43 @@
+package exporter
+
+// Agent self-metrics
+var (
+	ChecksTotal              Counter // Checks run by the monitors
+	CheckFailuresTotal       Counter // Checks that ended down
+	PocketBaseWriteFailures  Counter // Failed record writes to PocketBase, including spooled ones
+	ActiveMonitors           Gauge   // Services currently monitored
+	SchedulerLag             Gauge   // Delay between a check being due and starting, in seconds
+	SchedulerQueueDepth      Gauge   // Due checks waiting for a free worker
+	SchedulerMissedDeadlines Counter // Runs skipped because the previous run was still queued or running
+	SinkResultsDropped       Counter // Results a sink dropped because its queue was full or retries ran out
+	DNSAnswerChanges         Counter // DNS checks whose answers differed from the previous check
+)
+
+// AgentFamilies returns the agent's own counters as metric families
+func AgentFamilies() []Family {
+	return []Family{
+		{Name: "regional_check_checks_total", Help: "Checks run by this agent.", Type: "counter",
+			Samples: []Sample{{Value: ChecksTotal.Value()}}},
+		{Name: "regional_check_check_failures_total", Help: "Checks that ended with the service down.", Type: "counter",
+			Samples: []Sample{{Value: CheckFailuresTotal.Value()}}},
+		{Name: "regional_check_pocketbase_write_failures_total", Help: "Failed record writes to PocketBase.", Type: "counter",
+			Samples: []Sample{{Value: PocketBaseWriteFailures.Value()}}},
+		{Name: "regional_check_sink_results_dropped_total", Help: "Results dropped by a result sink.", Type: "counter",
+			Samples: []Sample{{Value: SinkResultsDropped.Value()}}},
+		{Name: "regional_check_active_monitors", Help: "Services currently monitored by this agent.", Type: "gauge",
+			Samples: []Sample{{Value: ActiveMonitors.Value()}}},
+		{Name: "regional_check_scheduler_lag_seconds", Help: "Delay between the last check being due and starting.", Type: "gauge",
+			Samples: []Sample{{Value: SchedulerLag.Value()}}},
+		{Name: "regional_check_scheduler_queue_depth", Help: "Due checks waiting for a free worker.", Type: "gauge",
+			Samples: []Sample{{Value: SchedulerQueueDepth.Value()}}},
+		{Name: "regional_check_scheduler_missed_deadlines_total", Help: "Checks skipped because the previous run was still pending.", Type: "counter",
+			Samples: []Sample{{Value: SchedulerMissedDeadlines.Value()}}},
+		{Name: "regional_check_dns_answer_changes_total", Help: "DNS checks whose answers changed since the previous check.", Type: "counter",
+			Samples: []Sample{{Value: DNSAnswerChanges.Value()}}},
+	}
+}
+
+// Gather returns all metric families exported on /metrics
+func Gather() []Family {
+	return append(ProbeFamilies(), AgentFamilies()...)
+}
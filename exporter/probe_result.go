@@ -0,0 +1,46 @@
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"service-operation/types"
+)
+
+// ProbeFamiliesFunc returns the type specific /probe metrics of a result
+type ProbeFamiliesFunc func(result *types.OperationResult) []Family
+
+var (
+	probeFamiliesMu sync.RWMutex
+	probeFamilies   = make(map[types.OperationType]ProbeFamiliesFunc)
+)
+
+// RegisterProbeFamilies maps an operation type to its /probe metrics.
+// Check types register themselves through the checkers registry.
+func RegisterProbeFamilies(opType types.OperationType, fn ProbeFamiliesFunc) {
+	probeFamiliesMu.Lock()
+	defer probeFamiliesMu.Unlock()
+	probeFamilies[opType] = fn
+}
+
+// ResultFamilies renders a single probe result with blackbox_exporter metric names, for /probe
+func ResultFamilies(result *types.OperationResult, duration time.Duration) []Family {
+	families := []Family{
+		GaugeFamily("probe_success", "Whether the probe succeeded.", BoolValue(result.Success)),
+		GaugeFamily("probe_duration_seconds", "How long the probe took to complete in seconds.", duration.Seconds()),
+	}
+
+	probeFamiliesMu.RLock()
+	fn, ok := probeFamilies[result.Type]
+	probeFamiliesMu.RUnlock()
+	if ok {
+		families = append(families, fn(result)...)
+	}
+
+	return families
+}
+
+// GaugeFamily is a gauge with a single unlabeled sample
+func GaugeFamily(name, help string, value float64) Family {
+	return Family{Name: name, Help: help, Type: "gauge", Samples: []Sample{{Value: value}}}
+}
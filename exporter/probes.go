@@ -0,0 +1,124 @@
+package exporter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"service-operation/types"
+)
+
+// ProbeLabels identify the service a probe result belongs to
+type ProbeLabels struct {
+	ServiceID string
+	Service   string
+	Type      string
+	Region    string
+	Agent     string
+}
+
+func (l ProbeLabels) labels() []Label {
+	return []Label{
+		{Name: "service_id", Value: l.ServiceID},
+		{Name: "service", Value: l.Service},
+		{Name: "type", Value: l.Type},
+		{Name: "region", Value: l.Region},
+		{Name: "agent", Value: l.Agent},
+	}
+}
+
+// probeResult is the latest result of one monitored service
+type probeResult struct {
+	labels    ProbeLabels
+	status    string
+	result    types.OperationResult
+	hasResult bool
+	timestamp time.Time
+}
+
+var (
+	probesMu sync.RWMutex
+	probes   = make(map[string]probeResult)
+)
+
+// RecordProbe stores the latest probe result of a service. result may be nil when the check errored.
+func RecordProbe(labels ProbeLabels, status string, result *types.OperationResult) {
+	probe := probeResult{
+		labels:    labels,
+		status:    status,
+		timestamp: time.Now(),
+	}
+	if result != nil {
+		probe.result = *result
+		probe.hasResult = true
+	}
+
+	probesMu.Lock()
+	probes[labels.ServiceID] = probe
+	probesMu.Unlock()
+}
+
+// RemoveProbe drops the metrics of a service that is no longer monitored
+func RemoveProbe(serviceID string) {
+	probesMu.Lock()
+	delete(probes, serviceID)
+	probesMu.Unlock()
+}
+
+// ProbeFamilies returns the latest probe results as metric families
+func ProbeFamilies() []Family {
+	up := Family{Name: "regional_check_probe_up", Help: "Whether the last check of the service succeeded (1 for up or warning, 0 for down).", Type: "gauge"}
+	warning := Family{Name: "regional_check_probe_warning", Help: "Whether the last check of the service crossed a warning threshold.", Type: "gauge"}
+	responseTime := Family{Name: "regional_check_probe_response_time_seconds", Help: "Response time of the last check.", Type: "gauge"}
+	packetLoss := Family{Name: "regional_check_probe_packet_loss_percent", Help: "Packet loss of the last ping check.", Type: "gauge"}
+	httpStatus := Family{Name: "regional_check_probe_http_status_code", Help: "HTTP status code returned by the last HTTP check.", Type: "gauge"}
+	dnsRecords := Family{Name: "regional_check_probe_dns_records", Help: "Number of records returned by the last DNS check.", Type: "gauge"}
+	lastRun := Family{Name: "regional_check_probe_last_run_timestamp_seconds", Help: "Unix time of the last check.", Type: "gauge"}
+
+	probesMu.RLock()
+	defer probesMu.RUnlock()
+
+	// Sorted for stable output between scrapes
+	ids := make([]string, 0, len(probes))
+	for id := range probes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		probe := probes[id]
+		labels := probe.labels.labels()
+
+		up.Samples = append(up.Samples, Sample{Labels: labels, Value: BoolValue(probe.status == "up" || probe.status == "warning")})
+		warning.Samples = append(warning.Samples, Sample{Labels: labels, Value: BoolValue(probe.status == "warning")})
+		lastRun.Samples = append(lastRun.Samples, Sample{Labels: labels, Value: float64(probe.timestamp.UnixNano()) / 1e9})
+
+		if !probe.hasResult {
+			continue
+		}
+		result := probe.result
+
+		responseTime.Samples = append(responseTime.Samples, Sample{Labels: labels, Value: result.ResponseTime.Seconds()})
+
+		switch result.Type {
+		case types.OperationPing:
+			packetLoss.Samples = append(packetLoss.Samples, Sample{Labels: labels, Value: result.PacketLoss})
+		case types.OperationHTTP:
+			if result.HTTPStatusCode > 0 {
+				httpStatus.Samples = append(httpStatus.Samples, Sample{Labels: labels, Value: float64(result.HTTPStatusCode)})
+			}
+		case types.OperationDNS:
+			dnsRecords.Samples = append(dnsRecords.Samples, Sample{Labels: labels, Value: float64(len(result.DNSRecords))})
+		}
+	}
+
+	return []Family{up, warning, responseTime, packetLoss, httpStatus, dnsRecords, lastRun}
+}
+
+// BoolValue is the sample value of a boolean, 1 or 0
+func BoolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"service-operation/shared/checkers"
 )
 
 func (h *OperationHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
@@ -13,7 +15,14 @@ func (h *OperationHandler) HandleHealth(w http.ResponseWriter, r *http.Request)
 		"service":   "service-operation",
 		"timestamp": time.Now().Unix(),
 		"version":   "1.0.0",
-		"operations": []string{"ping", "dns", "tcp", "http"},
+		"operations": checkers.Types(),
+	}
+
+	if h.registration != nil {
+		if registered, reason := h.registration.Registered(); !registered {
+			health["status"] = "degraded: not registered"
+			health["reason"] = reason
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -6,7 +6,12 @@ import (
 	"service-operation/types"
 )
 
+// saveMetricsToPocketBase runs off the request path: checking the credentials may sign in to
+// PocketBase, which blocks while the backend is unreachable
 func (h *OperationHandler) saveMetricsToPocketBase(result *types.OperationResult, serviceID string) {
+	if !h.pbClient.IsAuthenticated() {
+		return
+	}
 	metricsSaver := savers.NewMetricsSaver(h.pbClient)
 	metricsSaver.SaveMetricsToPocketBase(result, serviceID)
 }
@@ -2,18 +2,47 @@
 package handlers
 
 import (
+	"log"
+
 	"service-operation/config"
 	"service-operation/pocketbase"
+	"service-operation/types"
+	"service-operation/uptime"
 )
 
 type OperationHandler struct {
-	config   *config.Config
-	pbClient *pocketbase.PocketBaseClient
+	config       *config.Config
+	pbClient     *pocketbase.PocketBaseClient
+	probeModules map[string]types.OperationRequest
+	registration RegistrationStatus
+	uptime       *uptime.Tracker
+}
+
+// RegistrationStatus reports whether the agent has completed its backend registration
+type RegistrationStatus interface {
+	Registered() (bool, string)
 }
 
 func NewOperationHandler(cfg *config.Config, pbClient *pocketbase.PocketBaseClient) *OperationHandler {
+	probeModules, err := config.LoadProbeModules(cfg.ProbeModulesFile)
+	if err != nil {
+		log.Printf("Warning: Failed to load probe modules, using built-in modules: %v", err)
+		probeModules = config.DefaultProbeModules()
+	}
+
 	return &OperationHandler{
-		config:   cfg,
-		pbClient: pbClient,
+		config:       cfg,
+		pbClient:     pbClient,
+		probeModules: probeModules,
 	}
 }
+
+// SetRegistrationStatus makes /health report the agent as degraded until registration completes
+func (h *OperationHandler) SetRegistrationStatus(status RegistrationStatus) {
+	h.registration = status
+}
+
+// SetUptimeTracker exposes the rolling uptime of monitored services on /uptime
+func (h *OperationHandler) SetUptimeTracker(tracker *uptime.Tracker) {
+	h.uptime = tracker
+}
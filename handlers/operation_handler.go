@@ -6,7 +6,7 @@ import (
 	"net/http"
 	"time"
 
-	"service-operation/operations"
+	"service-operation/shared/checkers"
 	"service-operation/types"
 )
 
@@ -42,48 +42,24 @@ func (h *OperationHandler) HandleOperation(w http.ResponseWriter, r *http.Reques
 		req.Timeout = int(h.config.MaxTimeout.Seconds())
 	}
 
-	timeout := time.Duration(req.Timeout) * time.Second
-	var result *types.OperationResult
-	var err error
-
-	switch req.Type {
-	case types.OperationPing:
-		pingOp := operations.NewPingOperation(timeout)
-		result, err = pingOp.Execute(req.Host, req.Count)
-		
-	case types.OperationDNS:
-		dnsOp := operations.NewDNSOperation(timeout)
-		query := req.Query
-		if query == "" {
-			query = "A"
-		}
-		result, err = dnsOp.Execute(req.Host, query)
-		
-	case types.OperationTCP:
-		if req.Port <= 0 {
-			http.Error(w, "Port is required for TCP operations", http.StatusBadRequest)
-			return
-		}
-		tcpOp := operations.NewTCPOperation(timeout)
-		result, err = tcpOp.Execute(req.Host, req.Port)
-		
-	case types.OperationHTTP:
-		httpOp := operations.NewHTTPOperation(timeout)
-		url := req.URL
-		if url == "" {
-			url = req.Host
-		}
-		method := req.Method
-		if method == "" {
-			method = "GET"
-		}
-		result, err = httpOp.Execute(url, method)
-		
-	default:
+	checker, ok := checkers.Lookup(string(req.Type))
+	if !ok {
 		http.Error(w, "Invalid operation type", http.StatusBadRequest)
 		return
 	}
 
+	if err := checker.ParseRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeout := time.Duration(req.Timeout) * time.Second
+	// The check is canceled when the client disconnects
+	result, err := checker.Execute(r.Context(), req, timeout)
+	if r.Context().Err() != nil {
+		return
+	}
+
 	if err != nil {
 		result = &types.OperationResult{
 			Type:    req.Type,
@@ -95,7 +71,7 @@ func (h *OperationHandler) HandleOperation(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Save metrics to PocketBase if available
-	if h.pbClient != nil && h.pbClient.IsAuthenticated() {
+	if h.pbClient != nil {
 		go h.saveMetricsToPocketBase(result, req.ServiceID)
 	}
 
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/shared/checkers"
+	"service-operation/types"
+)
+
+// scrapeTimeoutOffset leaves room to return the response before Prometheus gives up on the scrape
+const scrapeTimeoutOffset = 500 * time.Millisecond
+
+// HandleProbe serves blackbox_exporter style probes: /probe?target=<target>&module=<module>
+func (h *OperationHandler) HandleProbe(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "Target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	moduleName := r.URL.Query().Get("module")
+	if moduleName == "" {
+		moduleName = "http_2xx"
+	}
+	module, ok := h.probeModules[moduleName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown module %q", moduleName), http.StatusBadRequest)
+		return
+	}
+
+	checker, ok := checkers.Lookup(string(module.Type))
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown probe type %q in module %q", module.Type, moduleName), http.StatusBadRequest)
+		return
+	}
+
+	req := module
+	applyProbeTarget(&req, target)
+	if err := checker.ParseRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeout := h.probeTimeout(r, req.Timeout)
+	start := time.Now()
+	result, err := checker.Execute(r.Context(), req, timeout)
+	duration := time.Since(start)
+
+	if err != nil {
+		log.Printf("Probe %s of %s failed: %v", moduleName, target, err)
+		result = &types.OperationResult{Type: req.Type, Host: req.Host, Port: req.Port, Error: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", exporter.ContentType)
+	if err := exporter.Write(w, exporter.ResultFamilies(result, duration)); err != nil {
+		log.Printf("Failed to write probe metrics: %v", err)
+	}
+}
+
+// probeTimeout uses the module timeout, bounded by the agent maximum and the Prometheus scrape timeout
+func (h *OperationHandler) probeTimeout(r *http.Request, moduleTimeout int) time.Duration {
+	timeout := h.config.DefaultTimeout
+	if moduleTimeout > 0 {
+		timeout = time.Duration(moduleTimeout) * time.Second
+	}
+	if timeout > h.config.MaxTimeout {
+		timeout = h.config.MaxTimeout
+	}
+
+	if header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); header != "" {
+		if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+			scrapeTimeout := time.Duration(seconds*float64(time.Second)) - scrapeTimeoutOffset
+			if scrapeTimeout > 0 && scrapeTimeout < timeout {
+				timeout = scrapeTimeout
+			}
+		}
+	}
+	return timeout
+}
+
+// applyProbeTarget fills the request target from the probe target parameter. HTTP targets
+// are URLs (http:// is assumed without a scheme); other types take a host or host:port.
+func applyProbeTarget(req *types.OperationRequest, target string) {
+	if req.Type == types.OperationHTTP {
+		if !strings.Contains(target, "://") {
+			target = "http://" + target
+		}
+		req.URL = target
+		return
+	}
+
+	if strings.Contains(target, "://") {
+		if u, err := url.Parse(target); err == nil {
+			target = u.Host
+		}
+	}
+
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		req.Host = host
+		if p, err := strconv.Atoi(port); err == nil {
+			req.Port = p
+		}
+		return
+	}
+	req.Host = strings.Trim(target, "[]")
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"service-operation/exporter"
+)
+
+// HandlePrometheusMetrics serves the latest probe results and agent metrics in the Prometheus text format
+func (h *OperationHandler) HandlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", exporter.ContentType)
+	if err := exporter.Write(w, exporter.Gather()); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
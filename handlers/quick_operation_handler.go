@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"service-operation/types"
 )
@@ -40,6 +41,23 @@ func (h *OperationHandler) HandleQuickOperation(w http.ResponseWriter, r *http.R
 		req.Query = query
 	}
 
+	if server := r.URL.Query().Get("dns_server"); server != "" {
+		req.DNSServer = server
+	}
+
+	if protocol := r.URL.Query().Get("dns_protocol"); protocol != "" {
+		req.DNSProtocol = protocol
+	}
+
+	if expected := r.URL.Query().Get("dns_expected"); expected != "" {
+		req.DNSExpected = strings.Split(expected, ",")
+		req.DNSExpectedMatch = r.URL.Query().Get("dns_expected_match")
+	}
+
+	if dnssec := r.URL.Query().Get("dnssec"); dnssec != "" {
+		req.DNSSEC, _ = strconv.ParseBool(dnssec)
+	}
+
 	if url := r.URL.Query().Get("url"); url != "" {
 		req.URL = url
 	}
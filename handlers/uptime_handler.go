@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// HandleUptime lists the rolling uptime and response time percentiles of all tracked services
+func (h *OperationHandler) HandleUptime(w http.ResponseWriter, r *http.Request) {
+	if h.uptime == nil {
+		http.Error(w, "Uptime tracking is not enabled", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"services": h.uptime.All(),
+	})
+}
+
+// HandleServiceUptime returns the rolling uptime of one service: /uptime/{id}
+func (h *OperationHandler) HandleServiceUptime(w http.ResponseWriter, r *http.Request) {
+	if h.uptime == nil {
+		http.Error(w, "Uptime tracking is not enabled", http.StatusNotFound)
+		return
+	}
+
+	stats, ok := h.uptime.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "No checks recorded for this service", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
@@ -4,6 +4,7 @@ package handlers
 import (
 	"encoding/json"
 
+	"service-operation/shared/checkers"
 	"service-operation/types"
 )
 
@@ -23,21 +24,10 @@ func formatResultDetails(result *types.OperationResult) string {
 	}
 
 	// Add type-specific details
-	switch result.Type {
-	case types.OperationPing:
-		details["packets_sent"] = result.PacketsSent
-		details["packets_recv"] = result.PacketsRecv
-		details["packet_loss"] = result.PacketLoss
-		details["avg_rtt"] = result.AvgRTT
-	case types.OperationHTTP:
-		details["status_code"] = result.HTTPStatusCode
-		details["method"] = result.HTTPMethod
-		details["content_length"] = result.ContentLength
-	case types.OperationTCP:
-		details["tcp_connected"] = result.TCPConnected
-	case types.OperationDNS:
-		details["dns_records"] = result.DNSRecords
-		details["dns_type"] = result.DNSType
+	if checker, ok := checkers.Lookup(string(result.Type)); ok {
+		for key, value := range checker.Details(result) {
+			details[key] = value
+		}
 	}
 
 	jsonData, _ := json.Marshal(details)
@@ -2,10 +2,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/gorilla/mux"
@@ -13,64 +15,73 @@ import (
 	"service-operation/handlers"
 	"service-operation/monitoring"
 	"service-operation/pocketbase"
+	"service-operation/sinks"
+	"service-operation/spool"
+	"service-operation/uptime"
 )
 
 func main() {
 	cfg := config.Load()
 	
-	// Initialize PocketBase client (no credentials required)
+	// Initialize PocketBase client. Credentials are optional; without them the
+	// collections must allow public access.
 	var pbClient *pocketbase.PocketBaseClient
 	var monitoringService *monitoring.MonitoringService
-	var regionalConfig *config.RegionalConfigManager
+	var bootstrap *agentBootstrap
+	var resultSink *sinks.FanOut
+
+	// Rolling uptime per service, kept across restarts
+	uptimeTracker, err := uptime.New(filepath.Join(cfg.StateDir, "uptime.json"))
+	if err != nil {
+		log.Printf("Warning: Starting with empty uptime history: %v", err)
+	}
 	
-	if cfg.PocketBaseEnabled {
+	if cfg.ServicesFile != "" {
+		// Standalone mode: services come from a local file and results go to local sinks
+		var err error
+		resultSink, err = sinks.NewFromConfig(cfg, nil)
+		if err != nil {
+			log.Fatalf("Failed to set up result sinks: %v", err)
+		}
+
+		monitoringService = monitoring.NewStandaloneMonitoringService(cfg, monitoring.NewFileServiceSource(cfg.ServicesFile), resultSink)
+		monitoringService.SetUptimeTracker(uptimeTracker)
+		go monitoringService.Start()
+		log.Printf("🎯 Standalone monitoring active: services from %s, results to %s", cfg.ServicesFile, resultSink.Name())
+	} else if cfg.PocketBaseEnabled {
 		var err error
-		pbClient, err = pocketbase.NewPocketBaseClient(cfg.PocketBaseURL)
+		pbClient, err = pocketbase.NewPocketBaseClientWithAuth(cfg.PocketBaseURL, pocketbase.AuthConfig{
+			Collection: cfg.PocketBaseAuthCollection,
+			Identity:   cfg.PocketBaseIdentity,
+			Password:   cfg.PocketBasePassword,
+			AgentID:    cfg.AgentID,
+			AgentToken: cfg.Token,
+		})
 		if err != nil {
 			log.Printf("Warning: Failed to initialize PocketBase client: %v", err)
 		} else {
-			if err := pbClient.TestConnection(); err != nil {
-				log.Printf("Warning: PocketBase connection test failed: %v", err)
-			} else {
-				// Initialize regional configuration manager
-				regionalConfig = config.NewRegionalConfigManager(cfg, pbClient)
-				
-				// Validate regional configuration first
-				if err := regionalConfig.ValidateRegionalConfig(); err != nil {
-					log.Printf("Error: Invalid regional configuration: %v", err)
-					log.Printf("Please set REGION_NAME and AGENT_ID environment variables")
-					log.Printf("Monitoring will not start without valid regional configuration")
+			// Buffer result writes on disk while the backend is unreachable
+			if cfg.SpoolEnabled {
+				resultSpool, err := spool.New(filepath.Join(cfg.StateDir, "spool"),
+					int64(cfg.SpoolMaxMB)*1024*1024, int64(cfg.SpoolSegmentMB)*1024*1024)
+				if err != nil {
+					log.Printf("Warning: Offline spool disabled: %v", err)
 				} else {
-					// Load or create regional service configuration
-					regionalService, err := regionalConfig.LoadOrCreateRegionalService()
-					if err != nil {
-						log.Printf("Error: Failed to setup regional configuration: %v", err)
-						log.Printf("Monitoring will not start without valid regional configuration")
-					} else {
-						// Final validation - ensure all required fields are present
-						if regionalService.RegionName == "" || regionalService.AgentID == "" {
-							log.Printf("Error: Invalid regional service configuration - region_name: '%s', agent_id: '%s'", 
-								regionalService.RegionName, regionalService.AgentID)
-							log.Printf("Monitoring will not start without valid regional configuration")
-						} else {
-							// Initialize and start monitoring service with regional support
-							monitoringService = monitoring.NewMonitoringServiceWithRegional(pbClient, regionalService)
-							go monitoringService.Start()
-							//log.Printf("✅ Regional monitoring started successfully with multi-assignment support")
-							//log.Printf("   Region: %s", regionalService.RegionName)
-							//log.Printf("   Agent ID: %s", regionalService.AgentID)
-							//log.Printf("   Agent IP: %s", regionalService.AgentIPAddress)
-							//log.Printf("   Multi-Assignment Support: Services can be assigned using comma-separated values")
-							//log.Printf("   Example: region_name='us-east,eu-west' agent_id='agent1,agent2'")
-							//log.Printf("   This agent will monitor services where its region AND agent ID appear in the assignments")
-						}
-					}
+					pbClient.SetSpool(resultSpool)
 				}
 			}
+
+			// Connect and register in the background, retrying until the backend is available
+			bootstrap = newAgentBootstrap(cfg, pbClient, uptimeTracker)
+			go bootstrap.run()
 		}
 	}
 	
 	handler := handlers.NewOperationHandler(cfg, pbClient)
+	handler.SetUptimeTracker(uptimeTracker)
+	if bootstrap != nil {
+		handler.SetRegistrationStatus(bootstrap)
+	}
 
 	router := mux.NewRouter()
 
@@ -87,20 +98,22 @@ func main() {
 	// Health check
 	router.HandleFunc("/health", handler.HandleHealth).Methods("GET")
 
+	// Prometheus metrics
+	router.HandleFunc("/metrics", handler.HandlePrometheusMetrics).Methods("GET")
+
+	// Rolling uptime and response time percentiles per service
+	router.HandleFunc("/uptime", handler.HandleUptime).Methods("GET")
+	router.HandleFunc("/uptime/{id}", handler.HandleServiceUptime).Methods("GET")
+
+	// Blackbox exporter compatible probe endpoint
+	router.HandleFunc("/probe", handler.HandleProbe).Methods("GET")
+
 	log.Printf(" - Regional Check Agent starting on port %s", cfg.Port)
 	if pbClient != nil {
 		log.Printf(" - Backenbd integration enabled at %s ", pbClient.GetBaseURL())
 	}
-	if monitoringService != nil && regionalConfig != nil {
-		regionName, agentID := regionalConfig.GetRegionalInfo()
-		log.Printf("🎯 Regional monitoring active: Region=%s, Agent=%s", regionName, agentID)
-		//log.Printf("⚡ Service filtering: Supports comma-separated multi-region/multi-agent assignments")
-		//log.Printf("💡 Service Assignment Examples:")
-		//log.Printf("   - Single: region_name='%s' agent_id='%s'", regionName, agentID)
-		//log.Printf("   - Multi: region_name='us-east,%s,eu-west' agent_id='agent1,%s,agent3'", regionName, agentID)
-	} else {
-		//log.Printf("❌ Regional monitoring disabled - configuration validation failed")
-		//log.Printf("💡 Set REGION_NAME and AGENT_ID environment variables to enable monitoring")
+	if bootstrap != nil {
+		log.Printf(" - Regional monitoring starts once the agent is registered with the backend")
 	}
 	
 	//log.Printf("🔗 Available endpoints:")
@@ -111,21 +124,32 @@ func main() {
 	//log.Printf("  GET  /health - Health check")
 	//log.Printf("📋 Supported operations: ping, dns, tcp, http")
 
-	// Setup graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: router}
 	go func() {
-		<-c
-		log.Println("🛑 Shutting down monitoring service...")
-		if monitoringService != nil {
-			monitoringService.Stop()
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
 		}
-		log.Println("✅ Regional Check Agent stopped")
-		os.Exit(0)
 	}()
 
-	if err := http.ListenAndServe(":"+cfg.Port, router); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Setup graceful shutdown
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+	signal.Stop(c) // A second signal exits immediately
+
+	log.Printf("🛑 Shutting down, waiting up to %v for running checks and pending writes...", cfg.ShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Drain checks, flush sinks and the spool and mark the agent offline before the API goes away
+	if monitoringService != nil {
+		monitoringService.Shutdown(ctx)
+	}
+	if bootstrap != nil {
+		bootstrap.shutdown(ctx)
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown: %v", err)
 	}
+	log.Println("✅ Regional Check Agent stopped")
 }
\ No newline at end of file
@@ -0,0 +1,118 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"service-operation/pocketbase"
+	"service-operation/statecache"
+)
+
+// assignmentsSaveInterval is how often changed service assignments are written to the cache
+const assignmentsSaveInterval = 10 * time.Second
+
+// SetAssignmentCache persists assigned services to cache and resumes from it when the
+// service source is unreachable at startup
+func (ms *MonitoringService) SetAssignmentCache(cache *statecache.Cache) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.cache = cache
+}
+
+// cachedAssignments returns the last known assigned services when nothing has been loaded
+// yet, e.g. after a restart during a backend outage
+func (ms *MonitoringService) cachedAssignments() ([]pocketbase.Service, bool) {
+	ms.mu.RLock()
+	cache, loaded := ms.cache, len(ms.services) > 0
+	ms.mu.RUnlock()
+
+	if cache == nil || loaded {
+		return nil, false
+	}
+
+	services, savedAt, err := cache.LoadServices()
+	if err != nil {
+		log.Printf("No cached service assignments available: %v", err)
+		return nil, false
+	}
+
+	log.Printf("📦 Monitoring %d services from cached assignments saved %s ago", len(services), time.Since(savedAt).Round(time.Second))
+	return services, true
+}
+
+// persistAssignments marks the assigned services as changed, to be written to the cache by
+// assignmentsLoop. The caller must hold ms.mu.
+func (ms *MonitoringService) persistAssignments() {
+	ms.cacheDirty = true
+}
+
+// assignmentsLoop writes changed assignments to the cache periodically until the service stops
+func (ms *MonitoringService) assignmentsLoop() {
+	ticker := time.NewTicker(assignmentsSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ms.saveAssignments()
+		case <-ms.stopChan:
+			return
+		}
+	}
+}
+
+// saveAssignments saves the assigned services so monitoring survives a restart during a
+// backend outage. Only the copy is made under ms.mu, realtime events are not held up by
+// encoding and writing the list.
+func (ms *MonitoringService) saveAssignments() {
+	ms.mu.Lock()
+	if ms.cache == nil || !ms.cacheDirty {
+		ms.mu.Unlock()
+		return
+	}
+	cache := ms.cache
+	services := make([]pocketbase.Service, 0, len(ms.services))
+	for _, service := range ms.services {
+		services = append(services, service)
+	}
+	ms.cacheDirty = false
+	ms.mu.Unlock()
+
+	ms.persistMu.Lock()
+	defer ms.persistMu.Unlock()
+
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+
+	// Every check updates the service records, so only write when the assignment itself changed
+	key := assignmentKey(services)
+	if key == ms.persistedKey {
+		return
+	}
+
+	if err := cache.SaveServices(services); err != nil {
+		log.Printf("⚠️  Failed to cache service assignments: %v", err)
+		ms.mu.Lock()
+		ms.cacheDirty = true
+		ms.mu.Unlock()
+		return
+	}
+	ms.persistedKey = key
+}
+
+// assignmentKey identifies an assignment list, ignoring fields changed by status updates
+func assignmentKey(services []pocketbase.Service) string {
+	stripped := make([]pocketbase.Service, len(services))
+	for i, service := range services {
+		service.Status = ""
+		service.Updated = ""
+		stripped[i] = service
+	}
+
+	encoded, err := json.Marshal(stripped)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
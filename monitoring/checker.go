@@ -1,4 +1,3 @@
-
 package monitoring
 
 import (
@@ -6,132 +5,197 @@ import (
 	"strings"
 	"time"
 
-	"service-operation/operations"
+	"service-operation/exporter"
 	"service-operation/pocketbase"
+	"service-operation/shared/checkers"
 	"service-operation/shared/savers"
+	"service-operation/sinks"
 	"service-operation/types"
 )
 
-func (ms *MonitoringService) performCheck(service pocketbase.Service) {
-	// First, fetch the latest service status from PocketBase to ensure we have current data
-	latestService, err := ms.pbClient.GetService(service.ID)
-	if err != nil {
-		log.Printf("Failed to fetch latest service status for %s: %v", service.Name, err)
+func (ms *MonitoringService) performCheck(monitor *ServiceMonitor) {
+	// Use the latest version of the service from the local cache, which is kept current by the
+	// realtime subscription (or polling). Paused and unassigned services are removed from it.
+	latestService, ok := ms.getCachedService(monitor.serviceID)
+	if !ok {
 		return
 	}
 
-	// Respect the service status - don't check paused services
-	if latestService.Status == "paused" {
-		return // Silently skip paused services
-	}
-
-	// Validate that this service is still assigned to our region and agent (supports comma-separated values)
-	if !pocketbase.IsAssignedToRegionAndAgent(*latestService, ms.regionName, ms.agentID) {
-		log.Printf("⚠️  Skipping check for %s: no longer assigned to region=%s, agent=%s", 
-			latestService.Name, ms.regionName, ms.agentID)
+	checker, ok := checkers.Lookup(latestService.ServiceType)
+	if !ok {
+		log.Printf("Unknown service type: %s for service %s", latestService.ServiceType, latestService.Name)
 		return
 	}
 
-	timeout := 10 * time.Second // Default timeout
-	var result *types.OperationResult
-	
-	serviceType := strings.ToLower(latestService.ServiceType)
-	
 	// Single log message for check start
-	//log.Printf("Checking %s (%s)", latestService.Name, serviceType)
-	
-	switch serviceType {
-	case "ping", "icmp":
-		pingOp := operations.NewPingOperation(timeout)
-		host := latestService.Host
-		if host == "" {
-			host = latestService.URL
-		}
-		result, err = pingOp.Execute(host, 1) // Single ping for monitoring
-		
-	case "dns":
-		dnsOp := operations.NewDNSOperation(timeout)
-		host := latestService.Host
-		if host == "" {
-			host = latestService.Domain
-		}
-		// Default to A record, but could be made configurable
-		queryType := "A"
-		result, err = dnsOp.Execute(host, queryType)
-		
-	case "tcp":
-		tcpOp := operations.NewTCPOperation(timeout)
-		host := latestService.Host
-		if host == "" {
-			host = latestService.URL
-		}
-		port := latestService.Port
-		if port <= 0 {
-			port = 80 // Default port
-		}
-		result, err = tcpOp.Execute(host, port)
-		
-	case "http", "https":
-		httpOp := operations.NewHTTPOperation(timeout)
-		url := latestService.URL
-		if url == "" {
-			url = latestService.Host
-		}
-		result, err = httpOp.Execute(url, "GET")
-		
-	default:
-		log.Printf("Unknown service type: %s for service %s", latestService.ServiceType, latestService.Name)
-		return
+	//log.Printf("Checking %s (%s)", latestService.Name, latestService.ServiceType)
+
+	result, err := ms.executeWithRetries(monitor, checker, latestService)
+	if result == nil && err == nil {
+		return // Monitor was stopped during the check
 	}
 
-	// Determine status based on result
-	status := "down"
+	if previous, changed := monitor.trackDNSAnswer(result); changed {
+		exporter.DNSAnswerChanges.Inc()
+		log.Printf("🔄 %s: DNS answer changed from [%s] to [%s]", latestService.Name,
+			strings.Join(previous, ", "), strings.Join(result.DNSRecords, ", "))
+	}
+
+	// Determine the probe status based on result
+	probeStatus := "down"
 	errorMessage := ""
 	responseTime := int64(0)
 	
 	if err != nil {
 		errorMessage = err.Error()
-		log.Printf("❌ %s failed: %v", latestService.Name, err)
 	} else if result != nil {
 		responseTime = result.ResponseTime.Milliseconds()
-		if result.Success {
-			status = "up"
-			log.Printf("✅ %s: %.0fms", latestService.Name, float64(responseTime))
-		} else {
-			status = "down"
+		probeStatus = savers.GetResultStatus(result)
+		if probeStatus != "up" {
 			errorMessage = result.Error
-			log.Printf("❌ %s failed: %s", latestService.Name, errorMessage)
 		}
 	}
 
-	// Only update service status if the service is not paused
-	// Check one more time before updating to prevent race conditions
-	currentService, err := ms.pbClient.GetService(latestService.ID)
-	if err != nil {
-		log.Printf("Failed to verify service status before update for %s: %v", latestService.Name, err)
+	// Only flip the service status after enough consecutive failures or successes
+	failureThreshold, recoveryThreshold := ms.thresholdsFor(latestService)
+	status, changed := monitor.state.record(probeStatus, failureThreshold, recoveryThreshold)
+
+	switch {
+	case changed && status == "down":
+		log.Printf("🔴 %s is DOWN after %d consecutive failures: %s", latestService.Name, monitor.state.consecutiveFailures, errorMessage)
+	case changed && status == "up":
+		log.Printf("🟢 %s is UP after %d consecutive successes", latestService.Name, monitor.state.consecutiveSuccesses)
+	case probeStatus == "down":
+		log.Printf("❌ %s failed (%d/%d): %s", latestService.Name, monitor.state.consecutiveFailures, failureThreshold, errorMessage)
+	case probeStatus == "warning":
+		log.Printf("⚠️  %s: %s", latestService.Name, errorMessage)
+	default:
+		log.Printf("✅ %s: %.0fms", latestService.Name, float64(responseTime))
+	}
+
+	// The service may have been paused or reassigned while the check was running
+	if _, ok := ms.getCachedService(latestService.ID); !ok {
+		log.Printf("⚠️  Skipping status update for %s: paused or reassigned during check", latestService.Name)
 		return
 	}
-	
-	if currentService.Status == "paused" {
-		return // Silently skip status update for paused services
+
+	// Export the latest probe result for Prometheus
+	regionName, agentID := ms.GetRegionalInfo()
+	exporter.ChecksTotal.Inc()
+	if probeStatus == "down" {
+		exporter.CheckFailuresTotal.Inc()
 	}
+	exporter.RecordProbe(exporter.ProbeLabels{
+		ServiceID: latestService.ID,
+		Service:   latestService.Name,
+		Type:      latestService.ServiceType,
+		Region:    regionName,
+		Agent:     agentID,
+	}, probeStatus, result)
 
-	// Final assignment validation before updating status
-	if !pocketbase.IsAssignedToRegionAndAgent(*currentService, ms.regionName, ms.agentID) {
-		log.Printf("⚠️  Skipping status update for %s: assignment changed during check", latestService.Name)
+	// Rolling uptime and response time percentiles, attached to the result for the sinks
+	uptimeStats := ms.recordUptime(latestService.ID, latestService.Name, probeStatus, responseTime)
+
+	// Hand the result to the result sinks (PocketBase, files, metrics backends)
+	ms.mu.RLock()
+	sink := ms.sink
+	ms.mu.RUnlock()
+
+	if sink == nil {
 		return
 	}
+	if err := sink.Write(sinks.Result{
+		Timestamp:     time.Now(),
+		ServiceID:     latestService.ID,
+		ServiceName:   latestService.Name,
+		ServiceType:   latestService.ServiceType,
+		Region:        regionName,
+		Agent:         agentID,
+		Status:        status,
+		ProbeStatus:   probeStatus,
+		StatusChanged: changed,
+		ResponseTime:  responseTime,
+		Error:         errorMessage,
+		Result:        result,
+		Uptime:        uptimeStats,
+		Service:       latestService,
+	}); err != nil {
+		log.Printf("Failed to write result for %s: %v", latestService.Name, err)
+	}
+}
+
+// executeWithRetries runs a check, retrying failed probes with a delay between attempts.
+// Retries stop once another attempt would not finish within the heartbeat interval, so an
+// outage does not hold a worker past the next run. It returns a nil result and error when
+// the monitor is stopped during the check.
+func (ms *MonitoringService) executeWithRetries(monitor *ServiceMonitor, checker checkers.Checker, service pocketbase.Service) (*types.OperationResult, error) {
+	req := checker.RequestFromService(service)
+	maxRetries, retryDelay := ms.retriesFor(service)
+	deadline := time.Now().Add(heartbeatInterval(service))
+
+	var result *types.OperationResult
+	var err error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if time.Now().Add(retryDelay + ms.config.RequestTimeout).After(deadline) {
+				break
+			}
+			select {
+			case <-time.After(retryDelay):
+			case <-monitor.ctx.Done():
+				return nil, nil
+			}
+		}
+
+		result, err = checker.Execute(monitor.ctx, req, ms.config.RequestTimeout)
+		if monitor.ctx.Err() != nil {
+			return nil, nil
+		}
+		if err == nil && result != nil && result.Success {
+			return result, nil
+		}
+	}
+
+	return result, err
+}
+
+// retriesFor returns the retry count and delay for a service, falling back to the agent configuration
+func (ms *MonitoringService) retriesFor(service pocketbase.Service) (int, time.Duration) {
+	maxRetries := ms.config.MaxRetries
+	switch {
+	case service.MaxRetries < 0:
+		maxRetries = 0 // -1 disables retries; 0 is an unset field in PocketBase
+	case service.MaxRetries > 0:
+		maxRetries = service.MaxRetries
+	}
 
-	// Update service status in PocketBase only if not paused and still assigned
-	if err := ms.pbClient.UpdateServiceStatus(latestService.ID, status, responseTime, errorMessage); err != nil {
-		log.Printf("Failed to update service status for %s: %v", latestService.Name, err)
+	retryDelay := ms.config.RetryDelay
+	if service.RetryInterval > 0 {
+		retryDelay = time.Duration(service.RetryInterval) * time.Second
 	}
 
-	// Save metrics data in ONE place to prevent duplicates
-	if result != nil {
-		// Get regional information from the monitoring service
-		regionName, agentID := ms.GetRegionalInfo()
-		metricsSaver := savers.NewMetricsSaverWithRegion(ms.pbClient, regionName, agentID)
-		metricsSaver.SaveMetricsForService(*latestService, result)
+	return maxRetries, retryDelay
+}
+
+// thresholdsFor returns how many consecutive failures mark a service down and how many
+// consecutive successes bring it back up
+func (ms *MonitoringService) thresholdsFor(service pocketbase.Service) (int, int) {
+	failureThreshold := ms.config.FailureThreshold
+	if service.FailureThreshold > 0 {
+		failureThreshold = service.FailureThreshold
+	}
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	recoveryThreshold := ms.config.RecoveryThreshold
+	if service.RecoveryThreshold > 0 {
+		recoveryThreshold = service.RecoveryThreshold
+	}
+	if recoveryThreshold < 1 {
+		recoveryThreshold = 1
 	}
-}
\ No newline at end of file
+
+	return failureThreshold, recoveryThreshold
+}
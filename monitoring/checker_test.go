@@ -0,0 +1,79 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"service-operation/config"
+	"service-operation/pocketbase"
+	"service-operation/shared/checkers"
+	"service-operation/types"
+)
+
+// failingChecker fails every probe immediately
+type failingChecker struct {
+	checkers.Checker
+	attempts int
+}
+
+func (c *failingChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	return types.OperationRequest{ServiceID: service.ID}
+}
+
+func (c *failingChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	c.attempts++
+	return &types.OperationResult{Error: "down"}, nil
+}
+
+func TestRetriesFor(t *testing.T) {
+	ms := &MonitoringService{config: &config.Config{MaxRetries: 3, RetryDelay: 2 * time.Second}}
+
+	tests := []struct {
+		maxRetries, retryInterval int
+		wantRetries               int
+		wantDelay                 time.Duration
+	}{
+		{maxRetries: 0, retryInterval: 0, wantRetries: 3, wantDelay: 2 * time.Second},
+		{maxRetries: 5, retryInterval: 10, wantRetries: 5, wantDelay: 10 * time.Second},
+		{maxRetries: -1, retryInterval: 0, wantRetries: 0, wantDelay: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		retries, delay := ms.retriesFor(pocketbase.Service{MaxRetries: tt.maxRetries, RetryInterval: tt.retryInterval})
+		if retries != tt.wantRetries || delay != tt.wantDelay {
+			t.Errorf("retriesFor(%d, %d) = %d, %v, want %d, %v", tt.maxRetries, tt.retryInterval, retries, delay, tt.wantRetries, tt.wantDelay)
+		}
+	}
+}
+
+func TestExecuteWithRetriesStopsAtHeartbeatInterval(t *testing.T) {
+	ms := &MonitoringService{config: &config.Config{MaxRetries: 100, RetryDelay: 100 * time.Millisecond, RequestTimeout: 400 * time.Millisecond}}
+	monitor := &ServiceMonitor{ctx: context.Background()}
+	checker := &failingChecker{}
+
+	start := time.Now()
+	result, err := ms.executeWithRetries(monitor, checker, pocketbase.Service{HeartbeatInterval: 1})
+	elapsed := time.Since(start)
+
+	if err != nil || result == nil || result.Success {
+		t.Fatalf("result %+v, error %v, want the failed result", result, err)
+	}
+	if elapsed >= time.Second {
+		t.Errorf("retries took %v, longer than the heartbeat interval", elapsed)
+	}
+	// Retries start every 100ms until a delay plus a full timeout no longer fits in the second
+	if checker.attempts < 2 || checker.attempts > 7 {
+		t.Errorf("%d attempts, want 2 to 7", checker.attempts)
+	}
+}
+
+func TestExecuteWithRetriesDisabled(t *testing.T) {
+	ms := &MonitoringService{config: &config.Config{MaxRetries: 3, RetryDelay: time.Millisecond, RequestTimeout: time.Millisecond}}
+	checker := &failingChecker{}
+
+	ms.executeWithRetries(&ServiceMonitor{ctx: context.Background()}, checker, pocketbase.Service{MaxRetries: -1})
+	if checker.attempts != 1 {
+		t.Errorf("%d attempts with retries disabled, want 1", checker.attempts)
+	}
+}
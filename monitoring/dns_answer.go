@@ -0,0 +1,55 @@
+package monitoring
+
+import (
+	"sort"
+
+	"service-operation/types"
+)
+
+// dnsAnswer is the answer set of the last DNS check of a monitor
+type dnsAnswer struct {
+	query   string   // Host, record type and nameserver the answers belong to
+	records []string // Sorted answer values
+}
+
+// trackDNSAnswer compares the answers of a DNS result with those of the previous check and marks
+// the result when they changed. Only NOERROR and NXDOMAIN responses count, so timeouts and server
+// failures do not register as changes. Returns the previous answers when they changed.
+func (monitor *ServiceMonitor) trackDNSAnswer(result *types.OperationResult) ([]string, bool) {
+	if result == nil || result.Type != types.OperationDNS {
+		return nil, false
+	}
+	if result.DNSRcode != "NOERROR" && result.DNSRcode != "NXDOMAIN" {
+		return nil, false
+	}
+
+	current := &dnsAnswer{
+		query:   result.Host + " " + result.DNSType + " @" + result.DNSServer,
+		records: append([]string{}, result.DNSRecords...),
+	}
+	sort.Strings(current.records)
+
+	previous := monitor.dnsAnswer
+	monitor.dnsAnswer = current
+
+	// The first answer, or the first after the query was edited, is the new baseline
+	if previous == nil || previous.query != current.query || equalStrings(previous.records, current.records) {
+		return nil, false
+	}
+
+	result.DNSAnswerChanged = true
+	result.DNSPreviousRecords = previous.records
+	return previous.records, true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package monitoring
+
+import (
+	"fmt"
+	"testing"
+
+	"service-operation/types"
+)
+
+func TestTrackDNSAnswer(t *testing.T) {
+	result := func(rcode string, records ...string) *types.OperationResult {
+		return &types.OperationResult{Type: types.OperationDNS, Host: "example.com", DNSType: "A", DNSServer: "1.1.1.1:53", DNSRcode: rcode, DNSRecords: records}
+	}
+
+	steps := []struct {
+		name     string
+		result   *types.OperationResult
+		changed  bool
+		previous []string
+	}{
+		{name: "first answer is the baseline", result: result("NOERROR", "192.0.2.2", "192.0.2.1")},
+		{name: "same records in another order", result: result("NOERROR", "192.0.2.1", "192.0.2.2")},
+		{name: "server failure is not a change", result: result("SERVFAIL")},
+		{name: "timeout is not a change", result: result("")},
+		{name: "new record", result: result("NOERROR", "192.0.2.1", "192.0.2.3"), changed: true, previous: []string{"192.0.2.1", "192.0.2.2"}},
+		{name: "name removed", result: result("NXDOMAIN"), changed: true, previous: []string{"192.0.2.1", "192.0.2.3"}},
+		{name: "still removed", result: result("NXDOMAIN")},
+		{name: "other result types are ignored", result: &types.OperationResult{Type: types.OperationHTTP, DNSRcode: "NOERROR"}},
+	}
+
+	monitor := &ServiceMonitor{}
+	for _, step := range steps {
+		previous, changed := monitor.trackDNSAnswer(step.result)
+		if changed != step.changed || fmt.Sprint(previous) != fmt.Sprint(step.previous) {
+			t.Errorf("%s: changed %v from %v, want %v from %v", step.name, changed, previous, step.changed, step.previous)
+		}
+		if step.result.DNSAnswerChanged != step.changed {
+			t.Errorf("%s: result marked changed %v", step.name, step.result.DNSAnswerChanged)
+		}
+	}
+
+	// Editing the query starts a new baseline
+	edited := result("NOERROR", "198.51.100.1")
+	edited.DNSServer = "8.8.8.8:53"
+	if _, changed := monitor.trackDNSAnswer(edited); changed {
+		t.Error("answer of an edited query reported as changed")
+	}
+}
@@ -2,51 +2,89 @@
 package monitoring
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"service-operation/exporter"
 	"service-operation/pocketbase"
 )
 
 type ServiceMonitor struct {
-	service  pocketbase.Service
-	ticker   *time.Ticker
-	stopChan chan bool
+	serviceID string
+	service   pocketbase.Service // Configuration the monitor is running with, guarded by ms.mu
+	schedule  *scheduledCheck
+	ctx       context.Context // Canceled when the monitor is stopped, aborting an in-flight check
+	cancel    context.CancelFunc
+	state     *serviceState
+	dnsAnswer *dnsAnswer // Answers of the last DNS check, only used by the monitor's own checks
 }
 
-func (ms *MonitoringService) startMonitor(service pocketbase.Service) {
+// heartbeatInterval returns the check interval of a service
+func heartbeatInterval(service pocketbase.Service) time.Duration {
 	if service.HeartbeatInterval <= 0 {
-		service.HeartbeatInterval = 60 // Default to 60 seconds
+		return 60 * time.Second // Default to 60 seconds
 	}
+	return time.Duration(service.HeartbeatInterval) * time.Second
+}
 
+func (ms *MonitoringService) startMonitor(service pocketbase.Service) {
+	ctx, cancel := context.WithCancel(ms.ctx)
 	monitor := &ServiceMonitor{
-		service:  service,
-		ticker:   time.NewTicker(time.Duration(service.HeartbeatInterval) * time.Second),
-		stopChan: make(chan bool),
+		serviceID: service.ID,
+		service:   service,
+		ctx:       ctx,
+		cancel:    cancel,
+		state:     newServiceState(service.Status),
 	}
 
 	ms.activeServices[service.ID] = monitor
+	exporter.ActiveMonitors.Set(float64(len(ms.activeServices)))
 
 	//log.Printf("Starting monitor for service: %s (%s)", service.Name, service.ServiceType)
 
-	go func() {
-		// Perform initial check
-		ms.performCheck(service)
-		
-		for {
-			select {
-			case <-monitor.ticker.C:
-				ms.performCheck(service)
-			case <-monitor.stopChan:
-				monitor.ticker.Stop()
-				return
-			}
-		}
-	}()
+	// Checks run on the shared worker pool; the first one is spread out from the others
+	monitor.schedule = ms.scheduler.add(monitor, heartbeatInterval(service))
+}
+
+// reconfigureMonitor applies an edited service to a running monitor without restarting it.
+// The caller must hold ms.mu.
+func (ms *MonitoringService) reconfigureMonitor(monitor *ServiceMonitor, service pocketbase.Service) {
+	previous := monitor.service
+	if previous.Updated == service.Updated {
+		return
+	}
+	monitor.service = service
+
+	if oldInterval, newInterval := heartbeatInterval(previous), heartbeatInterval(service); oldInterval != newInterval {
+		log.Printf("🔄 %s: heartbeat interval changed from %v to %v", service.Name, oldInterval, newInterval)
+		ms.scheduler.setInterval(monitor.schedule, newInterval)
+	}
+
+	if targetChanged(previous, service) {
+		log.Printf("🔄 %s: target changed, checking %s now", service.Name, service.ServiceType)
+		ms.scheduler.trigger(monitor.schedule)
+	}
+}
+
+// targetChanged reports whether the check type or the probed endpoint of a service changed
+func targetChanged(previous, current pocketbase.Service) bool {
+	return previous.ServiceType != current.ServiceType ||
+		previous.URL != current.URL ||
+		previous.Host != current.Host ||
+		previous.Port != current.Port ||
+		previous.Domain != current.Domain ||
+		previous.DNSQueryType != current.DNSQueryType ||
+		previous.DNSServer != current.DNSServer ||
+		previous.DNSProtocol != current.DNSProtocol
 }
 
 func (ms *MonitoringService) stopMonitor(serviceID string, monitor *ServiceMonitor) {
 	log.Printf("Stopping monitor for service: %s", serviceID)
-	monitor.stopChan <- true
+	// Cancels a running check, including one that is waiting to retry
+	monitor.cancel()
+	ms.scheduler.remove(monitor.schedule)
 	delete(ms.activeServices, serviceID)
+	exporter.ActiveMonitors.Set(float64(len(ms.activeServices)))
+	exporter.RemoveProbe(serviceID)
 }
@@ -0,0 +1,112 @@
+package monitoring
+
+import (
+	"log"
+	"time"
+
+	"service-operation/pocketbase"
+)
+
+const (
+	realtimeMinBackoff = 5 * time.Second
+	realtimeMaxBackoff = 2 * time.Minute
+)
+
+// realtimeLoop keeps a realtime subscription to the services collection open. While it is
+// connected, changes are applied as they happen and polling is paused; when it drops the
+// monitoring loop falls back to polling until the subscription is re-established.
+func (ms *MonitoringService) realtimeLoop() {
+	backoff := realtimeMinBackoff
+
+	for {
+		err := ms.pbClient.SubscribeServices(ms.stopChan, ms.onRealtimeConnect, ms.applyServiceEvent)
+
+		wasActive := ms.setRealtimeActive(false)
+		if wasActive {
+			backoff = realtimeMinBackoff
+			log.Printf("⚠️  Realtime subscription lost, falling back to polling: %v", err)
+		} else if err != nil {
+			log.Printf("Realtime subscription unavailable, polling every 30s: %v", err)
+		}
+
+		select {
+		case <-ms.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > realtimeMaxBackoff {
+			backoff = realtimeMaxBackoff
+		}
+	}
+}
+
+// onRealtimeConnect resyncs the cache to pick up changes missed while disconnected
+func (ms *MonitoringService) onRealtimeConnect() {
+	log.Printf("📡 Realtime subscription active for services collection")
+	ms.setRealtimeActive(true)
+	ms.loadAndStartAssignedServices()
+}
+
+// applyServiceEvent starts, updates or stops the monitor for a changed service record
+func (ms *MonitoringService) applyServiceEvent(event pocketbase.ServiceEvent) {
+	service := event.Record
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if !ms.isRunning {
+		return
+	}
+
+	monitor, active := ms.activeServices[service.ID]
+	wanted := event.Action != "delete" &&
+		service.Status != "paused" &&
+		pocketbase.IsAssignedToRegionAndAgent(service, ms.regionName, ms.agentID)
+
+	if !wanted {
+		delete(ms.services, service.ID)
+		ms.persistAssignments()
+		if active {
+			log.Printf("🛑 Stopping monitoring: %s (%s)", service.Name, event.Action)
+			ms.stopMonitor(service.ID, monitor)
+		}
+		return
+	}
+
+	ms.services[service.ID] = service
+	ms.persistAssignments()
+
+	if !active {
+		log.Printf("✅ Starting monitoring: %s (%s)", service.Name, service.ServiceType)
+		ms.startMonitor(service)
+		return
+	}
+	ms.reconfigureMonitor(monitor, service)
+}
+
+// getCachedService returns the latest known version of an assigned service
+func (ms *MonitoringService) getCachedService(serviceID string) (pocketbase.Service, bool) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	service, ok := ms.services[serviceID]
+	return service, ok
+}
+
+func (ms *MonitoringService) isRealtimeActive() bool {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.realtimeActive
+}
+
+// setRealtimeActive updates the subscription state and returns the previous value
+func (ms *MonitoringService) setRealtimeActive(active bool) bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	previous := ms.realtimeActive
+	ms.realtimeActive = active
+	return previous
+}
@@ -104,6 +104,11 @@ func (rm *RegionalMonitor) checkConnection() {
 		rm.isOnline = true
 		log.Printf("Regional agent back online")
 	}
+
+	// Flush results buffered while the backend was unreachable
+	if err == nil {
+		go rm.pbClient.ReplaySpool()
+	}
 }
 
 func (rm *RegionalMonitor) updateConnectionStatus(status string) {
@@ -0,0 +1,296 @@
+package monitoring
+
+import (
+	"container/heap"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"service-operation/exporter"
+)
+
+// scheduler runs the checks of all monitors on a bounded pool of workers. Each monitor keeps a
+// fixed-rate slot grid; every run is jittered around its slot and first runs are spread out so
+// that a restart does not fire all checks at once.
+type scheduler struct {
+	workers     int
+	jitter      time.Duration // Maximum random offset of a run, capped at a tenth of the interval
+	startSpread time.Duration // First runs are spread over this window, capped at the interval
+	run         func(*ServiceMonitor)
+
+	mu       sync.Mutex
+	ready    *sync.Cond // Signals workers that the run queue is not empty
+	entries  scheduleHeap
+	queue    []*scheduledCheck // Due checks waiting for a worker
+	wake     chan struct{}
+	stopChan chan struct{}
+	stopped  bool
+	running  sync.WaitGroup // Checks currently executing
+}
+
+// scheduledCheck is the schedule of one monitor. All fields are guarded by scheduler.mu.
+type scheduledCheck struct {
+	monitor  *ServiceMonitor
+	interval time.Duration
+	slot     time.Time // Position on the fixed-rate grid
+	at       time.Time // Slot plus jitter
+	due      time.Time // When the queued run became due
+	index    int       // Position in the heap, -1 when not scheduled
+	pending  bool      // Queued or running
+	removed  bool
+}
+
+func newScheduler(workers int, jitter, startSpread time.Duration, run func(*ServiceMonitor)) *scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	s := &scheduler{
+		workers:     workers,
+		jitter:      jitter,
+		startSpread: startSpread,
+		run:         run,
+		wake:        make(chan struct{}, 1),
+		stopChan:    make(chan struct{}),
+	}
+	s.ready = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *scheduler) start() {
+	for i := 0; i < s.workers; i++ {
+		go s.worker()
+	}
+	go s.loop()
+}
+
+// stop ends the dispatch loop and lets idle workers exit. Running checks finish on their own.
+func (s *scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	s.queue = nil
+	exporter.SchedulerQueueDepth.Set(0)
+	close(s.stopChan)
+	s.ready.Broadcast()
+}
+
+// add schedules a monitor with its first run at a random point within the start spread
+func (s *scheduler) add(monitor *ServiceMonitor, interval time.Duration) *scheduledCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spread := s.startSpread
+	if spread > interval {
+		spread = interval
+	}
+	check := &scheduledCheck{monitor: monitor, interval: interval, index: -1}
+	check.slot = time.Now().Add(randomDuration(spread))
+	check.at = check.slot
+
+	heap.Push(&s.entries, check)
+	s.notify()
+	return check
+}
+
+// remove unschedules a monitor; a queued run is skipped
+func (s *scheduler) remove(check *scheduledCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	check.removed = true
+	if check.index >= 0 {
+		heap.Remove(&s.entries, check.index)
+	}
+}
+
+// setInterval moves a monitor to a new interval, with the next run one interval from now
+func (s *scheduler) setInterval(check *scheduledCheck, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if check.removed {
+		return
+	}
+	check.interval = interval
+	check.slot = time.Now().Add(interval)
+	check.at = check.slot.Add(s.jitterFor(interval))
+	heap.Fix(&s.entries, check.index)
+	s.notify()
+}
+
+// trigger queues an immediate run unless one is already pending. The slot grid is unchanged.
+func (s *scheduler) trigger(check *scheduledCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if check.removed || check.pending || s.stopped {
+		return
+	}
+	s.enqueue(check, time.Now())
+}
+
+// wait blocks until the running checks have finished or ctx expires. Call it after stop so
+// that no new checks start.
+func (s *scheduler) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *scheduler) loop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		for len(s.entries) > 0 && !s.entries[0].at.After(now) {
+			s.dispatch(s.entries[0], now)
+		}
+		wait := time.Hour
+		if len(s.entries) > 0 {
+			wait = s.entries[0].at.Sub(now)
+		}
+		s.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// dispatch queues a due check and moves it to its next slot. A check whose previous run is still
+// queued or running misses this deadline. The caller must hold s.mu.
+func (s *scheduler) dispatch(check *scheduledCheck, now time.Time) {
+	if check.pending {
+		exporter.SchedulerMissedDeadlines.Inc()
+	} else {
+		s.enqueue(check, check.at)
+	}
+
+	check.slot = check.slot.Add(check.interval)
+	if !check.slot.After(now) {
+		// The agent fell behind by more than an interval (e.g. the host was suspended)
+		behind := now.Sub(check.slot)/check.interval + 1
+		check.slot = check.slot.Add(behind * check.interval)
+	}
+	check.at = check.slot.Add(s.jitterFor(check.interval))
+	heap.Fix(&s.entries, check.index)
+}
+
+// enqueue hands a check to the workers. The caller must hold s.mu.
+func (s *scheduler) enqueue(check *scheduledCheck, due time.Time) {
+	check.pending = true
+	check.due = due
+	s.queue = append(s.queue, check)
+	exporter.SchedulerQueueDepth.Set(float64(len(s.queue)))
+	s.ready.Signal()
+}
+
+func (s *scheduler) worker() {
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.stopped {
+			s.ready.Wait()
+		}
+		if s.stopped {
+			s.mu.Unlock()
+			return
+		}
+		check := s.queue[0]
+		s.queue = s.queue[1:]
+		exporter.SchedulerQueueDepth.Set(float64(len(s.queue)))
+		removed, due := check.removed, check.due
+		s.running.Add(1)
+		s.mu.Unlock()
+
+		if !removed {
+			exporter.SchedulerLag.Set(time.Since(due).Seconds())
+			s.run(check.monitor)
+		}
+		s.running.Done()
+
+		s.mu.Lock()
+		check.pending = false
+		s.mu.Unlock()
+	}
+}
+
+// notify wakes the dispatch loop to recompute its timer. The caller must hold s.mu.
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jitterFor returns a random offset in [-j, j] with j the configured jitter, capped at a tenth of the interval
+func (s *scheduler) jitterFor(interval time.Duration) time.Duration {
+	j := s.jitter
+	if j > interval/10 {
+		j = interval / 10
+	}
+	if j <= 0 {
+		return 0
+	}
+	return randomDuration(2*j) - j
+}
+
+// randomDuration returns a random duration in [0, d)
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// scheduleHeap orders scheduled checks by their next run
+type scheduleHeap []*scheduledCheck
+
+func (h scheduleHeap) Len() int           { return len(h) }
+func (h scheduleHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduleHeap) Push(x interface{}) {
+	check := x.(*scheduledCheck)
+	check.index = len(*h)
+	*h = append(*h, check)
+}
+
+func (h *scheduleHeap) Pop() interface{} {
+	old := *h
+	check := old[len(old)-1]
+	old[len(old)-1] = nil
+	check.index = -1
+	*h = old[:len(old)-1]
+	return check
+}
@@ -0,0 +1,200 @@
+package monitoring
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduleHeapOrder(t *testing.T) {
+	base := time.Now()
+	var h scheduleHeap
+	checks := make(map[int]*scheduledCheck)
+	for _, offset := range []int{5, 1, 4, 2, 3} {
+		check := &scheduledCheck{at: base.Add(time.Duration(offset) * time.Second), index: -1}
+		checks[offset] = check
+		heap.Push(&h, check)
+	}
+
+	// Indexes follow the checks through swaps, so Fix and Remove hit the right entry
+	for i, check := range h {
+		if check.index != i {
+			t.Fatalf("check at position %d has index %d", i, check.index)
+		}
+	}
+	checks[5].at = base
+	heap.Fix(&h, checks[5].index)
+	heap.Remove(&h, checks[3].index)
+	if checks[3].index != -1 {
+		t.Errorf("removed check has index %d, want -1", checks[3].index)
+	}
+
+	var order []time.Duration
+	for h.Len() > 0 {
+		order = append(order, heap.Pop(&h).(*scheduledCheck).at.Sub(base))
+	}
+	want := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second}
+	if len(order) != len(want) {
+		t.Fatalf("popped %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("popped %v, want %v", order, want)
+		}
+	}
+}
+
+func TestJitterFor(t *testing.T) {
+	tests := []struct {
+		jitter, interval, max time.Duration
+	}{
+		{jitter: 0, interval: time.Minute, max: 0},
+		{jitter: 2 * time.Second, interval: time.Minute, max: 2 * time.Second},
+		{jitter: 30 * time.Second, interval: time.Minute, max: 6 * time.Second}, // Capped at a tenth
+		{jitter: time.Second, interval: 5 * time.Nanosecond, max: 0},
+	}
+
+	for _, tt := range tests {
+		s := newScheduler(1, tt.jitter, 0, nil)
+		for i := 0; i < 100; i++ {
+			if j := s.jitterFor(tt.interval); j < -tt.max || j > tt.max {
+				t.Errorf("jitter %v with interval %v = %v, want within ±%v", tt.jitter, tt.interval, j, tt.max)
+				break
+			}
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	s := newScheduler(1, 0, 0, nil)
+	interval := time.Minute
+	now := time.Now()
+	check := s.add(&ServiceMonitor{serviceID: "svc1"}, interval)
+	slot := check.slot
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A due check is queued and moves to its next slot on the grid
+	s.dispatch(check, now)
+	if len(s.queue) != 1 || !check.pending {
+		t.Fatalf("queue length %d, pending %v after the first dispatch", len(s.queue), check.pending)
+	}
+	if !check.slot.Equal(slot.Add(interval)) || !check.at.Equal(check.slot) {
+		t.Errorf("next slot %v, at %v, want %v", check.slot, check.at, slot.Add(interval))
+	}
+
+	// The previous run is still pending, so this deadline is missed instead of queued twice
+	s.dispatch(check, now)
+	if len(s.queue) != 1 {
+		t.Errorf("queue length %d, a pending check was queued again", len(s.queue))
+	}
+
+	// After falling behind by several intervals the slot skips ahead, staying on the grid
+	check.pending = false
+	s.queue = nil
+	check.slot = slot.Add(-10 * interval)
+	s.dispatch(check, now)
+	if !check.slot.After(now) || check.slot.After(now.Add(interval)) {
+		t.Errorf("slot %v after falling behind, want within one interval after %v", check.slot, now)
+	}
+	if check.slot.Sub(slot)%interval != 0 {
+		t.Errorf("slot %v left the grid of %v", check.slot, slot)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	s := newScheduler(1, 0, 0, nil)
+	check := s.add(&ServiceMonitor{serviceID: "svc1"}, time.Minute)
+	slot := check.slot
+
+	s.trigger(check)
+	s.trigger(check)
+	if len(s.queue) != 1 {
+		t.Errorf("queue length %d, want one run for two triggers", len(s.queue))
+	}
+	if !check.slot.Equal(slot) {
+		t.Errorf("trigger moved the slot from %v to %v", slot, check.slot)
+	}
+
+	removed := s.add(&ServiceMonitor{serviceID: "svc2"}, time.Minute)
+	s.remove(removed)
+	s.trigger(removed)
+	if len(s.queue) != 1 || len(s.entries) != 1 {
+		t.Errorf("queue length %d, %d entries after triggering a removed check", len(s.queue), len(s.entries))
+	}
+
+	other := s.add(&ServiceMonitor{serviceID: "svc3"}, time.Minute)
+	s.stop()
+	s.trigger(other)
+	if len(s.queue) != 0 {
+		t.Errorf("queue length %d, a stopped scheduler accepted a trigger", len(s.queue))
+	}
+}
+
+func TestSetInterval(t *testing.T) {
+	s := newScheduler(1, 10*time.Second, 0, nil)
+	first := s.add(&ServiceMonitor{serviceID: "svc1"}, time.Minute)
+	second := s.add(&ServiceMonitor{serviceID: "svc2"}, time.Minute)
+
+	before := time.Now()
+	s.setInterval(first, 10*time.Minute)
+	if first.interval != 10*time.Minute {
+		t.Errorf("interval %v, want 10m", first.interval)
+	}
+	if first.slot.Before(before.Add(10*time.Minute)) || first.slot.After(time.Now().Add(10*time.Minute)) {
+		t.Errorf("slot %v, want one interval from now", first.slot)
+	}
+	if d := first.at.Sub(first.slot); d < -10*time.Second || d > 10*time.Second {
+		t.Errorf("run %v from its slot, want within the jitter", d)
+	}
+	if s.entries[0] != second {
+		t.Error("heap was not reordered after the interval change")
+	}
+
+	s.remove(second)
+	s.setInterval(second, time.Second)
+	if second.interval != time.Minute {
+		t.Errorf("removed check changed its interval to %v", second.interval)
+	}
+}
+
+func TestSchedulerRunsChecks(t *testing.T) {
+	var mu sync.Mutex
+	runs := make(map[string]int)
+	s := newScheduler(2, 0, 0, func(monitor *ServiceMonitor) {
+		mu.Lock()
+		runs[monitor.serviceID]++
+		mu.Unlock()
+	})
+	s.start()
+
+	s.add(&ServiceMonitor{serviceID: "fast"}, 20*time.Millisecond)
+	slow := s.add(&ServiceMonitor{serviceID: "slow"}, time.Hour)
+	removed := s.add(&ServiceMonitor{serviceID: "removed"}, time.Hour)
+	s.remove(removed)
+
+	time.Sleep(30 * time.Millisecond)
+	s.trigger(slow)
+	time.Sleep(100 * time.Millisecond)
+	s.stop()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.wait(ctx); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if runs["fast"] < 3 {
+		t.Errorf("fast check ran %d times, want one run per interval", runs["fast"])
+	}
+	if runs["slow"] != 2 {
+		t.Errorf("slow check ran %d times, want the first run and the triggered one", runs["slow"])
+	}
+	if runs["removed"] != 0 {
+		t.Errorf("removed check ran %d times", runs["removed"])
+	}
+}
@@ -2,38 +2,66 @@
 package monitoring
 
 import (
+	"context"
 	"log"
+	"os"
 	"sync"
 	"time"
 
+	"service-operation/config"
 	"service-operation/pocketbase"
+	"service-operation/sinks"
+	"service-operation/statecache"
+	"service-operation/uptime"
 )
 
 type MonitoringService struct {
-	pbClient        *pocketbase.PocketBaseClient
+	config          *config.Config
+	pbClient        *pocketbase.PocketBaseClient // nil in standalone mode
+	source          ServiceSource
+	sink            sinks.ResultSink
+	cache           *statecache.Cache // Last known assignments, optional
+	persistedKey    string            // Last assignment list written to the cache, guarded by persistMu
+	persistMu       sync.Mutex        // Serializes cache writes
+	cacheDirty      bool              // Assignments changed since the last cache write
+	uptime          *uptime.Tracker   // Rolling uptime history, optional
 	activeServices  map[string]*ServiceMonitor
+	services        map[string]pocketbase.Service // Local cache of assigned services
+	realtimeActive  bool                          // Realtime subscription is live, polling is paused
 	regionalMonitor *RegionalMonitor
+	scheduler       *scheduler // Runs the checks of all monitors, created on Start
 	mu              sync.RWMutex
 	stopChan        chan bool
+	ctx             context.Context // Parent of the monitor contexts, canceled on Stop
+	cancel          context.CancelFunc
 	isRunning       bool
+	shutDown        bool // Shutdown was called, Start is a no-op from then on
 	regionName      string
 	agentID         string
 }
 
-func NewMonitoringService(pbClient *pocketbase.PocketBaseClient) *MonitoringService {
+func NewMonitoringService(cfg *config.Config, pbClient *pocketbase.PocketBaseClient) *MonitoringService {
 	return &MonitoringService{
+		config:          cfg,
 		pbClient:        pbClient,
+		source:          pbClient,
+		sink:            sinks.NewPocketBaseSink(pbClient),
 		activeServices:  make(map[string]*ServiceMonitor),
+		services:        make(map[string]pocketbase.Service),
 		regionalMonitor: NewRegionalMonitor(pbClient),
 		stopChan:        make(chan bool),
 		isRunning:       false,
 	}
 }
 
-func NewMonitoringServiceWithRegional(pbClient *pocketbase.PocketBaseClient, regionalService *pocketbase.RegionalService) *MonitoringService {
+func NewMonitoringServiceWithRegional(cfg *config.Config, pbClient *pocketbase.PocketBaseClient, regionalService *pocketbase.RegionalService) *MonitoringService {
 	return &MonitoringService{
+		config:          cfg,
 		pbClient:        pbClient,
+		source:          pbClient,
+		sink:            sinks.NewPocketBaseSink(pbClient),
 		activeServices:  make(map[string]*ServiceMonitor),
+		services:        make(map[string]pocketbase.Service),
 		regionalMonitor: NewRegionalMonitorWithService(pbClient, regionalService),
 		stopChan:        make(chan bool),
 		isRunning:       false,
@@ -42,6 +70,41 @@ func NewMonitoringServiceWithRegional(pbClient *pocketbase.PocketBaseClient, reg
 	}
 }
 
+// NewStandaloneMonitoringService monitors services from a local source without PocketBase and
+// writes results to sink
+func NewStandaloneMonitoringService(cfg *config.Config, source ServiceSource, sink sinks.ResultSink) *MonitoringService {
+	regionName, agentID := cfg.RegionName, cfg.AgentID
+	if regionName == "" {
+		regionName = "standalone"
+	}
+	if agentID == "" {
+		agentID, _ = os.Hostname()
+	}
+	if agentID == "" {
+		agentID = "standalone"
+	}
+
+	return &MonitoringService{
+		config:         cfg,
+		source:         source,
+		sink:           sink,
+		activeServices: make(map[string]*ServiceMonitor),
+		services:       make(map[string]pocketbase.Service),
+		stopChan:       make(chan bool),
+		isRunning:      false,
+		regionName:     regionName,
+		agentID:        agentID,
+	}
+}
+
+// SetResultSink replaces where check results are written, e.g. with a fan-out to several backends.
+// The sink is closed by Shutdown.
+func (ms *MonitoringService) SetResultSink(sink sinks.ResultSink) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.sink = sink
+}
+
 func (ms *MonitoringService) Start() {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
@@ -50,6 +113,9 @@ func (ms *MonitoringService) Start() {
 		log.Println("⚠️  Monitoring service is already running")
 		return
 	}
+	if ms.shutDown {
+		return // The agent shut down before monitoring started
+	}
 
 	// Critical validation: Ensure we have valid regional configuration
 	if ms.regionName == "" || ms.agentID == "" {
@@ -61,17 +127,28 @@ func (ms *MonitoringService) Start() {
 	}
 
 	ms.isRunning = true
+	ms.stopChan = make(chan bool)
+	ms.ctx, ms.cancel = context.WithCancel(context.Background())
+	ms.scheduler = newScheduler(ms.config.MaxConcurrentChecks, ms.config.CheckJitter, ms.config.CheckStartSpread, ms.performCheck)
+	ms.scheduler.start()
 	//log.Printf("🚀 Starting regional monitoring service with multi-assignment support")
 	//log.Printf("   Assigned Region: %s", ms.regionName)
 	//log.Printf("   Assigned Agent ID: %s", ms.agentID)
 	//log.Printf("   Filter Mode: Services with comma-separated region_name AND agent_id assignments supported")
 	//log.Printf("   Example: Service with region_name='us-east,eu-west' and agent_id='agent1,agent2' will be monitored")
 
-	// Start regional monitoring (connection status tracking)
-	ms.regionalMonitor.Start()
-
 	// Start the main monitoring loop
 	go ms.monitoringLoop()
+	go ms.uptimeLoop()
+	go ms.assignmentsLoop()
+
+	if ms.pbClient != nil {
+		// Start regional monitoring (connection status tracking)
+		ms.regionalMonitor.Start()
+
+		// Start the realtime subscription that keeps the cache current
+		go ms.realtimeLoop()
+	}
 }
 
 func (ms *MonitoringService) Stop() {
@@ -86,17 +163,83 @@ func (ms *MonitoringService) Stop() {
 	ms.isRunning = false
 	
 	// Stop regional monitoring
-	ms.regionalMonitor.Stop()
+	if ms.regionalMonitor != nil {
+		ms.regionalMonitor.Stop()
+	}
 	
 	// Stop all active monitors
 	for serviceID, monitor := range ms.activeServices {
 		ms.stopMonitor(serviceID, monitor)
 	}
+	ms.scheduler.stop()
+	ms.cancel()
 
-	ms.stopChan <- true
+	// Closing stops both the polling loop and the realtime subscription
+	close(ms.stopChan)
+}
+
+// Shutdown stops scheduling checks and waits for the running ones until ctx expires, canceling
+// any that are left. It then closes the result sink, flushes the offline spool and marks the
+// agent offline. Start does nothing after Shutdown, so a shutdown that races a pending Start
+// still wins.
+func (ms *MonitoringService) Shutdown(ctx context.Context) error {
+	ms.mu.Lock()
+	if ms.shutDown {
+		ms.mu.Unlock()
+		return nil
+	}
+	ms.shutDown = true
+	if !ms.isRunning {
+		// Not started yet; only the sink needs closing
+		sink := ms.sink
+		ms.mu.Unlock()
+		if sink != nil {
+			return sinks.CloseContext(ctx, sink)
+		}
+		return nil
+	}
+
+	log.Println("🛑 Stopping monitoring service, waiting for running checks...")
+	ms.isRunning = false
+	close(ms.stopChan)
+	ms.scheduler.stop()
+	scheduler := ms.scheduler
+	ms.mu.Unlock()
+
+	// Running checks need ms.mu to finish, so wait without holding it
+	err := scheduler.wait(ctx)
+	if err != nil {
+		log.Printf("⚠️  Shutdown deadline reached, canceling running checks")
+	}
+
+	ms.mu.Lock()
+	for serviceID, monitor := range ms.activeServices {
+		ms.stopMonitor(serviceID, monitor)
+	}
+	ms.cancel()
+	sink := ms.sink
+	ms.mu.Unlock()
+
+	if sink != nil {
+		if closeErr := sinks.CloseContext(ctx, sink); closeErr != nil {
+			log.Printf("⚠️  Failed to flush result sinks: %v", closeErr)
+		}
+	}
+	if ms.pbClient != nil {
+		ms.pbClient.FlushSpool(ctx)
+	}
+	ms.saveUptime()
+	ms.saveAssignments()
+	if ms.regionalMonitor != nil {
+		ms.regionalMonitor.Stop()
+	}
+	return err
 }
 
 func (ms *MonitoringService) GetRegionalInfo() (string, string) {
+	if ms.regionalMonitor == nil {
+		return ms.regionName, ms.agentID
+	}
 	return ms.regionalMonitor.GetRegionalInfo()
 }
 
@@ -110,7 +253,10 @@ func (ms *MonitoringService) monitoringLoop() {
 	for {
 		select {
 		case <-ticker.C:
-			ms.loadAndStartAssignedServices()
+			// Polling is only needed while the realtime subscription is down
+			if !ms.isRealtimeActive() {
+				ms.loadAndStartAssignedServices()
+			}
 		case <-ms.stopChan:
 			return
 		}
@@ -119,11 +265,16 @@ func (ms *MonitoringService) monitoringLoop() {
 
 func (ms *MonitoringService) loadAndStartAssignedServices() {
 	// Get services assigned to this agent (supports comma-separated assignments)
-	services, err := ms.pbClient.GetAssignedServices(ms.regionName, ms.agentID)
+	services, err := ms.source.GetAssignedServices(ms.regionName, ms.agentID)
+	fromCache := false
 	if err != nil {
 		log.Printf("❌ Failed to load assigned services for region='%s', agent='%s': %v", 
 			ms.regionName, ms.agentID, err)
-		return
+
+		// Resume from the last known assignments if nothing has been loaded yet
+		if services, fromCache = ms.cachedAssignments(); !fromCache {
+			return
+		}
 	}
 
 	ms.mu.Lock()
@@ -131,6 +282,7 @@ func (ms *MonitoringService) loadAndStartAssignedServices() {
 
 	// Track which services are currently assigned to this specific agent
 	assignedServiceIDs := make(map[string]bool)
+	ms.services = make(map[string]pocketbase.Service)
 	newServicesCount := 0
 	
 	for _, service := range services {
@@ -141,9 +293,11 @@ func (ms *MonitoringService) loadAndStartAssignedServices() {
 		}
 		
 		assignedServiceIDs[service.ID] = true
+		ms.services[service.ID] = service
 		
 		// Start monitoring if not already active
-		if _, exists := ms.activeServices[service.ID]; !exists {
+		monitor, exists := ms.activeServices[service.ID]
+		if !exists {
 			// Enhanced logging for multi-assignment support
 			regionList := pocketbase.SplitCommaValues(service.RegionName)
 			agentList := pocketbase.SplitCommaValues(service.AgentID)
@@ -154,6 +308,9 @@ func (ms *MonitoringService) loadAndStartAssignedServices() {
 			
 			ms.startMonitor(service)
 			newServicesCount++
+		} else {
+			// Apply edits such as a new heartbeat interval or target to the running monitor
+			ms.reconfigureMonitor(monitor, service)
 		}
 	}
 
@@ -168,6 +325,10 @@ func (ms *MonitoringService) loadAndStartAssignedServices() {
 		}
 	}
 
+	if !fromCache {
+		ms.persistAssignments()
+	}
+
 	// Status summary
 //	totalAssigned := len(services)
 //	totalActive := len(ms.activeServices)
@@ -0,0 +1,41 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+
+	"service-operation/config"
+	"service-operation/sinks"
+)
+
+// closeCountingSink records how often it was closed
+type closeCountingSink struct {
+	closes int
+}
+
+func (s *closeCountingSink) Name() string             { return "test" }
+func (s *closeCountingSink) Write(sinks.Result) error { return nil }
+func (s *closeCountingSink) Close() error {
+	s.closes++
+	return nil
+}
+
+func TestShutdownBeforeStart(t *testing.T) {
+	sink := &closeCountingSink{}
+	ms := NewStandaloneMonitoringService(&config.Config{RegionName: "eu-west", AgentID: "1"}, nil, sink)
+
+	if err := ms.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	ms.Start() // e.g. still pending in a goroutine when the signal arrived
+	if ms.isRunning {
+		t.Error("monitoring started after Shutdown")
+	}
+
+	if err := ms.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+	if sink.closes != 1 {
+		t.Errorf("sink closed %d times, want once", sink.closes)
+	}
+}
@@ -0,0 +1,60 @@
+package monitoring
+
+import (
+	"os"
+	"time"
+
+	"service-operation/config"
+	"service-operation/pocketbase"
+)
+
+// ServiceSource provides the services assigned to this agent
+type ServiceSource interface {
+	GetAssignedServices(regionName, agentID string) ([]pocketbase.Service, error)
+}
+
+// FileServiceSource reads services from a local YAML or JSON file for standalone mode. The
+// file is read on every load, so edits are picked up by the next poll.
+type FileServiceSource struct {
+	path string
+}
+
+func NewFileServiceSource(path string) *FileServiceSource {
+	return &FileServiceSource{path: path}
+}
+
+func (s *FileServiceSource) GetAssignedServices(regionName, agentID string) ([]pocketbase.Service, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := config.LoadServicesFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var assigned []pocketbase.Service
+	for _, service := range services {
+		if service.Status == "paused" {
+			continue
+		}
+
+		// Entries without an assignment belong to whichever agent reads the file
+		if service.RegionName == "" && service.AgentID == "" {
+			service.RegionName = regionName
+			service.AgentID = agentID
+		}
+		if !pocketbase.IsAssignedToRegionAndAgent(service, regionName, agentID) {
+			continue
+		}
+
+		// Lets running monitors notice edits to the file
+		if service.Updated == "" {
+			service.Updated = info.ModTime().Format(time.RFC3339Nano)
+		}
+		assigned = append(assigned, service)
+	}
+
+	return assigned, nil
+}
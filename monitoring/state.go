@@ -0,0 +1,41 @@
+package monitoring
+
+// serviceState tracks consecutive probe outcomes so a service only changes
+// status after several failed (or successful) checks in a row
+type serviceState struct {
+	status               string
+	consecutiveFailures  int
+	consecutiveSuccesses int
+}
+
+func newServiceState(initialStatus string) *serviceState {
+	switch initialStatus {
+	case "up", "down", "warning":
+	default:
+		initialStatus = "pending"
+	}
+	return &serviceState{status: initialStatus}
+}
+
+// record applies a probe status ("up", "warning" or "down") and returns the confirmed
+// service status and whether it changed
+func (s *serviceState) record(probeStatus string, failureThreshold, recoveryThreshold int) (string, bool) {
+	previous := s.status
+
+	if probeStatus == "down" {
+		s.consecutiveFailures++
+		s.consecutiveSuccesses = 0
+		if s.consecutiveFailures >= failureThreshold {
+			s.status = "down"
+		}
+	} else {
+		s.consecutiveSuccesses++
+		s.consecutiveFailures = 0
+		// Switching between up and warning is immediate, recovering from down or pending is not
+		if s.status == "up" || s.status == "warning" || s.consecutiveSuccesses >= recoveryThreshold {
+			s.status = probeStatus
+		}
+	}
+
+	return s.status, s.status != previous
+}
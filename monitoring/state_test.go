@@ -0,0 +1,70 @@
+package monitoring
+
+import "testing"
+
+func TestNewServiceState(t *testing.T) {
+	for initial, want := range map[string]string{"up": "up", "down": "down", "warning": "warning", "": "pending", "paused": "pending"} {
+		if got := newServiceState(initial).status; got != want {
+			t.Errorf("newServiceState(%q) status = %q, want %q", initial, got, want)
+		}
+	}
+}
+
+func TestServiceStateRecord(t *testing.T) {
+	type step struct {
+		probe   string
+		status  string
+		changed bool
+	}
+
+	tests := []struct {
+		name     string
+		initial  string
+		failures int
+		recovery int
+		steps    []step
+	}{
+		{
+			name: "down after consecutive failures", initial: "up", failures: 3, recovery: 2,
+			steps: []step{{"down", "up", false}, {"down", "up", false}, {"down", "down", true}, {"down", "down", false}},
+		},
+		{
+			name: "success resets the failure count", initial: "up", failures: 2, recovery: 2,
+			steps: []step{{"down", "up", false}, {"up", "up", false}, {"down", "up", false}, {"down", "down", true}},
+		},
+		{
+			name: "recovery needs consecutive successes", initial: "down", failures: 2, recovery: 3,
+			steps: []step{{"up", "down", false}, {"up", "down", false}, {"down", "down", false}, {"up", "down", false}, {"up", "down", false}, {"up", "up", true}},
+		},
+		{
+			name: "recovering with a warning", initial: "down", failures: 1, recovery: 2,
+			steps: []step{{"warning", "down", false}, {"warning", "warning", true}},
+		},
+		{
+			name: "up and warning switch immediately", initial: "up", failures: 3, recovery: 3,
+			steps: []step{{"warning", "warning", true}, {"up", "up", true}, {"up", "up", false}},
+		},
+		{
+			name: "pending waits for the thresholds", initial: "", failures: 2, recovery: 2,
+			steps: []step{{"up", "pending", false}, {"up", "up", true}},
+		},
+		{
+			name: "pending goes down", initial: "", failures: 2, recovery: 2,
+			steps: []step{{"down", "pending", false}, {"down", "down", true}},
+		},
+		{
+			name: "thresholds of one", initial: "up", failures: 1, recovery: 1,
+			steps: []step{{"down", "down", true}, {"up", "up", true}},
+		},
+	}
+
+	for _, tt := range tests {
+		state := newServiceState(tt.initial)
+		for i, s := range tt.steps {
+			status, changed := state.record(s.probe, tt.failures, tt.recovery)
+			if status != s.status || changed != s.changed {
+				t.Errorf("%s: step %d (%s) = %q, %v, want %q, %v", tt.name, i, s.probe, status, changed, s.status, s.changed)
+			}
+		}
+	}
+}
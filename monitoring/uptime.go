@@ -0,0 +1,60 @@
+package monitoring
+
+import (
+	"log"
+	"time"
+
+	"service-operation/uptime"
+)
+
+// uptimeSaveInterval is how often the rolling uptime history is written to disk
+const uptimeSaveInterval = 5 * time.Minute
+
+// SetUptimeTracker records every check in tracker, whose rolling uptime and response time
+// percentiles are attached to the results
+func (ms *MonitoringService) SetUptimeTracker(tracker *uptime.Tracker) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.uptime = tracker
+}
+
+// recordUptime adds a check to the uptime history and returns the service's updated stats
+func (ms *MonitoringService) recordUptime(serviceID, serviceName, probeStatus string, responseTime int64) *uptime.Stats {
+	ms.mu.RLock()
+	tracker := ms.uptime
+	ms.mu.RUnlock()
+
+	if tracker == nil {
+		return nil
+	}
+	stats := tracker.Record(serviceID, serviceName, time.Now(), probeStatus != "down", time.Duration(responseTime)*time.Millisecond)
+	return &stats
+}
+
+// uptimeLoop saves the uptime history periodically until the service stops
+func (ms *MonitoringService) uptimeLoop() {
+	ticker := time.NewTicker(uptimeSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ms.saveUptime()
+		case <-ms.stopChan:
+			return
+		}
+	}
+}
+
+func (ms *MonitoringService) saveUptime() {
+	ms.mu.RLock()
+	tracker := ms.uptime
+	ms.mu.RUnlock()
+
+	if tracker == nil {
+		return
+	}
+	if err := tracker.Save(); err != nil {
+		log.Printf("⚠️  Failed to save uptime history: %v", err)
+	}
+}
@@ -1,69 +1,168 @@
 package operations
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/miekg/dns"
+
 	"service-operation/types"
 )
 
+// Transports a DNS check can query its nameserver over
+const (
+	DNSProtocolUDP = "udp"
+	DNSProtocolTCP = "tcp"
+	DNSProtocolDoT = "dot" // DNS over TLS (RFC 7858)
+	DNSProtocolDoH = "doh" // DNS over HTTPS (RFC 8484)
+)
+
+// dnsUDPSize is the EDNS0 buffer size advertised in queries, so larger responses are not truncated
+const dnsUDPSize = 4096
+
+// dnsQueryTypes are the record types a check can query
+var dnsQueryTypes = map[string]uint16{
+	"A":      dns.TypeA,
+	"AAAA":   dns.TypeAAAA,
+	"MX":     dns.TypeMX,
+	"TXT":    dns.TypeTXT,
+	"CNAME":  dns.TypeCNAME,
+	"NS":     dns.TypeNS,
+	"SRV":    dns.TypeSRV,
+	"CAA":    dns.TypeCAA,
+	"PTR":    dns.TypePTR,
+	"SOA":    dns.TypeSOA,
+	"DS":     dns.TypeDS,
+	"DNSKEY": dns.TypeDNSKEY,
+}
+
 type DNSOperation struct {
-	timeout time.Duration
+	timeout       time.Duration
+	server        string
+	protocol      string
+	expected      []string
+	expectedMatch string
+	dnssec        bool
 }
 
 func NewDNSOperation(timeout time.Duration) *DNSOperation {
-	return &DNSOperation{timeout: timeout}
+	return &DNSOperation{timeout: timeout, protocol: DNSProtocolUDP}
+}
+
+// NewDNSOperationWithOptions creates a DNS operation that queries the configured nameserver and
+// checks the answers against the expected ones. The server is a host or host:port, or a URL for
+// DoH; when empty the first nameserver in /etc/resolv.conf is used.
+func NewDNSOperationWithOptions(timeout time.Duration, options *types.DNSOptions) *DNSOperation {
+	op := NewDNSOperation(timeout)
+	if options == nil {
+		return op
+	}
+
+	op.server = strings.TrimSpace(options.DNSServer)
+	if options.DNSProtocol != "" {
+		op.protocol = strings.ToLower(options.DNSProtocol)
+	}
+	op.expected = options.DNSExpected
+	op.expectedMatch = strings.ToLower(options.DNSExpectedMatch)
+	op.dnssec = options.DNSSEC
+	return op
+}
+
+// ValidateDNSQueryType checks that query is a supported record type; empty means A
+func ValidateDNSQueryType(query string) error {
+	if query == "" {
+		return nil
+	}
+	if _, ok := dnsQueryTypes[strings.ToUpper(query)]; !ok {
+		return fmt.Errorf("unsupported DNS query type %q: must be one of A, AAAA, MX, TXT, CNAME, NS, SRV, CAA, PTR, SOA, DS or DNSKEY", query)
+	}
+	return nil
 }
 
-func (d *DNSOperation) Execute(host, query string) (*types.OperationResult, error) {
+// ValidateDNSOptions checks the transport and the expected answer match mode
+func ValidateDNSOptions(options *types.DNSOptions) error {
+	if options == nil {
+		return nil
+	}
+
+	switch strings.ToLower(options.DNSProtocol) {
+	case "", DNSProtocolUDP, DNSProtocolTCP, DNSProtocolDoT, DNSProtocolDoH:
+	default:
+		return fmt.Errorf("invalid dns_protocol %q: must be udp, tcp, dot or doh", options.DNSProtocol)
+	}
+
+	switch strings.ToLower(options.DNSExpectedMatch) {
+	case "", DNSMatchExact, DNSMatchContains:
+	default:
+		return fmt.Errorf("invalid dns_expected_match %q: must be exact or contains", options.DNSExpectedMatch)
+	}
+	return nil
+}
+
+func (d *DNSOperation) Execute(ctx context.Context, host, query string) (*types.OperationResult, error) {
 	// Validate inputs
 	if host == "" {
 		return nil, fmt.Errorf("host cannot be empty")
 	}
-	
+	if err := ValidateDNSOptions(&types.DNSOptions{DNSProtocol: d.protocol, DNSExpectedMatch: d.expectedMatch}); err != nil {
+		return nil, err
+	}
+
 	if query == "" {
 		query = "A" // Default to A record
 	}
+	if err := ValidateDNSQueryType(query); err != nil {
+		return nil, err
+	}
+	qtype := dnsQueryTypes[strings.ToUpper(query)]
 
 	result := &types.OperationResult{
-		Type:      types.OperationDNS,
-		Host:      host,
-		DNSType:   query,
-		StartTime: time.Now(),
+		Type:        types.OperationDNS,
+		Host:        host,
+		DNSType:     query,
+		DNSProtocol: d.protocol,
+		StartTime:   time.Now(),
 	}
 
-	start := time.Now()
+	server, err := d.serverAddress()
+	if err != nil {
+		result.EndTime = time.Now()
+		result.Error = err.Error()
+		result.Details = d.createDetailedErrorMessage(err.Error(), host, query)
+		return result, nil
+	}
+	result.DNSServer = server
 
-	// Resolve the host first to get detailed info
-	var resolvedIPs []string
-	var err error
-
-	switch strings.ToUpper(query) {
-	case "A":
-		resolvedIPs, err = d.performARecordLookup(host)
-		
-	case "AAAA":
-		resolvedIPs, err = d.performAAAARecordLookup(host)
-		
-	case "MX":
-		resolvedIPs, err = d.performMXRecordLookup(host)
-		
-	case "TXT":
-		resolvedIPs, err = d.performTXTRecordLookup(host)
-		
-	case "CNAME":
-		resolvedIPs, err = d.performCNAMERecordLookup(host)
-		
-	case "NS":
-		resolvedIPs, err = d.performNSRecordLookup(host)
-		
-	default:
-		// Default to A record lookup for unknown types
-		resolvedIPs, err = d.performARecordLookup(host)
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
 	}
 
+	name := dns.Fqdn(host)
+	if qtype == dns.TypePTR {
+		// Reverse lookups take an IP and query its in-addr.arpa or ip6.arpa name
+		if reverse, err := dns.ReverseAddr(host); err == nil {
+			name = reverse
+		}
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.SetEdns0(dnsUDPSize, d.dnssec)
+	// With checking disabled a validating resolver returns bogus records, so the failure can be explained
+	msg.CheckingDisabled = d.dnssec
+
+	start := time.Now()
+	resp, size, err := d.exchange(ctx, msg, server)
 	result.ResponseTime = time.Since(start)
 	result.EndTime = time.Now()
 
@@ -71,155 +170,339 @@ func (d *DNSOperation) Execute(host, query string) (*types.OperationResult, erro
 		result.Error = err.Error()
 		result.Success = false
 		result.Details = d.createDetailedErrorMessage(err.Error(), host, query)
+		return result, nil
+	}
+
+	result.DNSRcode = dns.RcodeToString[resp.Rcode]
+	result.DNSAuthoritative = resp.Authoritative
+	result.DNSMsgSize = size
+	result.DNSAnswer = dnsRecords(resp.Answer)
+	result.DNSAuthority = dnsRecords(resp.Ns)
+	result.DNSAdditional = dnsRecords(resp.Extra)
+
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == qtype {
+			result.DNSRecords = append(result.DNSRecords, answerValue(rr))
+		}
+	}
+
+	if d.dnssec && (resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError) {
+		result.DNSSECStatus, result.DNSSECReason = d.validateDNSSEC(ctx, server, resp)
+		result.EndTime = time.Now()
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		result.Error = fmt.Sprintf("%s response from %s", result.DNSRcode, server)
+		if result.DNSSECStatus == DNSSECBogus {
+			result.Error += " (DNSSEC bogus: " + result.DNSSECReason + ")"
+		}
+		result.Details = d.createDetailedErrorMessage(result.DNSRcode, host, query)
+	} else if result.DNSSECStatus == DNSSECBogus {
+		result.Error = "DNSSEC validation failed: " + result.DNSSECReason
+		result.Details = d.createDetailedErrorMessage(result.Error, host, query)
+	} else if len(result.DNSRecords) == 0 {
+		result.Error = "No DNS records found"
+		result.Details = d.createDetailedErrorMessage("no records found", host, query)
+	} else if mismatch := matchExpectedAnswers(resp.Answer, qtype, d.expected, d.expectedMatch); mismatch != "" {
+		result.FailedAssertion = mismatch
+		result.Error = "Unexpected DNS answer: " + mismatch
+		result.Details = d.createDetailedErrorMessage(result.Error, host, query)
 	} else {
-		result.DNSRecords = resolvedIPs
-		result.Success = len(resolvedIPs) > 0
-		
-		if result.Success {
-			result.Details = d.createDetailedSuccessMessage(result, host, query, resolvedIPs)
-		} else {
-			result.Error = "No DNS records found"
-			result.Details = d.createDetailedErrorMessage("no records found", host, query)
+		result.Success = true
+		if result.DNSSECStatus == DNSSECInsecure || result.DNSSECStatus == DNSSECIndeterminate {
+			result.Warning = true
+			result.Error = fmt.Sprintf("DNSSEC %s: %s", result.DNSSECStatus, result.DNSSECReason)
 		}
+		result.Details = d.createDetailedSuccessMessage(result, host, query, result.DNSRecords)
 	}
 
 	return result, nil
 }
 
-func (d *DNSOperation) performARecordLookup(host string) ([]string, error) {
-	ips, err := net.LookupIP(host)
-	if err != nil {
-		return nil, err
+// serverAddress returns the host:port, or for DoH the URL, the query is sent to
+func (d *DNSOperation) serverAddress() (string, error) {
+	if d.protocol == DNSProtocolDoH {
+		if d.server == "" {
+			return "", fmt.Errorf("dns_server is required for DoH")
+		}
+		server := d.server
+		if !strings.Contains(server, "://") {
+			server = "https://" + server
+		}
+		u, err := url.Parse(server)
+		if err != nil {
+			return "", fmt.Errorf("invalid DoH server %q: %v", d.server, err)
+		}
+		if u.Path == "" {
+			u.Path = "/dns-query"
+		}
+		return u.String(), nil
 	}
-	
-	var ipv4Records []string
-	for _, ip := range ips {
-		if ipv4 := ip.To4(); ipv4 != nil {
-			ipv4Records = append(ipv4Records, ipv4.String())
+
+	port := "53"
+	if d.protocol == DNSProtocolDoT {
+		port = "853"
+	}
+
+	server := d.server
+	if server == "" {
+		conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return "", fmt.Errorf("no dns_server set and no system nameserver: %v", err)
 		}
+		if len(conf.Servers) == 0 {
+			return "", fmt.Errorf("no dns_server set and no nameserver in /etc/resolv.conf")
+		}
+		server = conf.Servers[0]
+	}
+
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server, nil
 	}
-	
-	return ipv4Records, nil
+	return net.JoinHostPort(strings.Trim(server, "[]"), port), nil
 }
 
-func (d *DNSOperation) performAAAARecordLookup(host string) ([]string, error) {
-	ips, err := net.LookupIP(host)
+// exchange sends the query and returns the response with its size in bytes on the wire
+func (d *DNSOperation) exchange(ctx context.Context, msg *dns.Msg, server string) (*dns.Msg, int, error) {
+	switch d.protocol {
+	case DNSProtocolDoH:
+		return exchangeHTTPS(ctx, msg, server)
+
+	case DNSProtocolDoT:
+		host, _, _ := net.SplitHostPort(server)
+		client := &dns.Client{Net: "tcp-tls", TLSConfig: &tls.Config{ServerName: host}}
+		return exchangeConn(ctx, client, msg, server)
+
+	case DNSProtocolTCP:
+		return exchangeConn(ctx, &dns.Client{Net: "tcp"}, msg, server)
+
+	default:
+		resp, size, err := exchangeConn(ctx, &dns.Client{Net: "udp"}, msg, server)
+		if err == nil && resp.Truncated {
+			// Retry over TCP like a resolver would, to get the full answer
+			return exchangeConn(ctx, &dns.Client{Net: "tcp"}, msg, server)
+		}
+		return resp, size, err
+	}
+}
+
+// exchangeConn runs a query over a UDP, TCP or TLS connection
+func exchangeConn(ctx context.Context, client *dns.Client, msg *dns.Msg, server string) (*dns.Msg, int, error) {
+	client.Dialer = &net.Dialer{} // The context bounds the dial instead of the client's 2s default
+
+	conn, err := client.DialContext(ctx, server)
 	if err != nil {
-		return nil, err
+		return nil, 0, queryError(ctx, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
 	}
-	
-	var ipv6Records []string
-	for _, ip := range ips {
-		if ipv6 := ip.To16(); ipv6 != nil && ip.To4() == nil {
-			ipv6Records = append(ipv6Records, ipv6.String())
+	// Unblock the read when the check is canceled
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	conn.UDPSize = dnsUDPSize
+	if err := conn.WriteMsg(msg); err != nil {
+		return nil, 0, queryError(ctx, err)
+	}
+
+	for {
+		raw, err := conn.ReadMsgHeader(nil)
+		if err != nil {
+			return nil, 0, queryError(ctx, err)
+		}
+
+		resp := new(dns.Msg)
+		if err := resp.Unpack(raw); err != nil {
+			return nil, 0, fmt.Errorf("invalid DNS response: %v", err)
+		}
+		if resp.Id != msg.Id {
+			continue // Late reply to an earlier query from the same port
 		}
+		return resp, len(raw), nil
 	}
-	
-	return ipv6Records, nil
 }
 
-func (d *DNSOperation) performMXRecordLookup(host string) ([]string, error) {
-	mxRecords, err := net.LookupMX(host)
+// exchangeHTTPS posts the query to a DoH endpoint
+func exchangeHTTPS(ctx context.Context, msg *dns.Msg, endpoint string) (*dns.Msg, int, error) {
+	query := msg.Copy()
+	query.Id = 0 // RFC 8484 recommends ID 0 so responses are cache friendly
+	packed, err := query.Pack()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	
-	var records []string
-	for _, mx := range mxRecords {
-		records = append(records, fmt.Sprintf("%s (priority: %d)", mx.Host, mx.Pref))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, 0, err
 	}
-	
-	return records, nil
-}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
 
-func (d *DNSOperation) performTXTRecordLookup(host string) ([]string, error) {
-	txtRecords, err := net.LookupTXT(host)
+	// A fresh connection per check, so the response time includes the handshake every time
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		DisableKeepAlives: true,
+		ForceAttemptHTTP2: true,
+	}}
+	httpResp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, queryError(ctx, err)
 	}
-	
-	return txtRecords, nil
-}
+	defer httpResp.Body.Close()
 
-func (d *DNSOperation) performCNAMERecordLookup(host string) ([]string, error) {
-	cname, err := net.LookupCNAME(host)
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("DoH server returned HTTP %d", httpResp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(httpResp.Body, dns.MaxMsgSize))
 	if err != nil {
-		return nil, err
+		return nil, 0, queryError(ctx, err)
 	}
-	
-	return []string{cname}, nil
+
+	resp := new(dns.Msg)
+	if err := resp.Unpack(raw); err != nil {
+		return nil, 0, fmt.Errorf("invalid DNS response: %v", err)
+	}
+	return resp, len(raw), nil
 }
 
-func (d *DNSOperation) performNSRecordLookup(host string) ([]string, error) {
-	nsRecords, err := net.LookupNS(host)
-	if err != nil {
-		return nil, err
+// queryError reports a canceled or timed out query in the terms used by the error details
+func queryError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.Canceled:
+		return fmt.Errorf("query canceled")
+	case context.DeadlineExceeded:
+		return fmt.Errorf("query timeout")
 	}
-	
-	var records []string
-	for _, ns := range nsRecords {
-		records = append(records, ns.Host)
+	return err
+}
+
+// dnsRecords converts a response section, leaving out the EDNS0 pseudo-record
+func dnsRecords(rrs []dns.RR) []types.DNSRecord {
+	var records []types.DNSRecord
+	for _, rr := range rrs {
+		header := rr.Header()
+		if header.Rrtype == dns.TypeOPT {
+			continue
+		}
+		records = append(records, types.DNSRecord{
+			Name:  header.Name,
+			Type:  dns.TypeToString[header.Rrtype],
+			TTL:   header.Ttl,
+			Value: strings.TrimPrefix(rr.String(), header.String()),
+		})
 	}
-	
-	return records, nil
+	return records
+}
+
+// answerValue formats an answer record the way dns_records has always reported it
+func answerValue(rr dns.RR) string {
+	switch record := rr.(type) {
+	case *dns.A:
+		return record.A.String()
+	case *dns.AAAA:
+		return record.AAAA.String()
+	case *dns.MX:
+		return fmt.Sprintf("%s (priority: %d)", record.Mx, record.Preference)
+	case *dns.TXT:
+		return strings.Join(record.Txt, "")
+	case *dns.CNAME:
+		return record.Target
+	case *dns.NS:
+		return record.Ns
+	case *dns.SRV:
+		return fmt.Sprintf("%s:%d (priority: %d, weight: %d)", record.Target, record.Port, record.Priority, record.Weight)
+	case *dns.CAA:
+		return fmt.Sprintf("%d %s %s", record.Flag, record.Tag, record.Value)
+	case *dns.PTR:
+		return record.Ptr
+	case *dns.SOA:
+		return fmt.Sprintf("%s %s (serial: %d)", record.Ns, record.Mbox, record.Serial)
+	}
+	// DS and DNSKEY keep their presentation format
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
 }
 
 func (d *DNSOperation) createDetailedSuccessMessage(result *types.OperationResult, host, queryType string, records []string) string {
 	var details strings.Builder
-	
+
 	// Success indicator with basic info
-	details.WriteString(fmt.Sprintf("🟢 DNS SUCCESS - %s query for %s", 
-		strings.ToUpper(queryType), host))
-	
+	indicator := "🟢 DNS SUCCESS"
+	if result.Warning {
+		indicator = "🟡 DNS WARNING"
+	}
+	details.WriteString(fmt.Sprintf("%s - %s query for %s via %s (%s)",
+		indicator, strings.ToUpper(queryType), host, result.DNSServer, result.DNSProtocol))
+
 	// Response time
-	details.WriteString(fmt.Sprintf(" | Response time: %.2fms", 
+	details.WriteString(fmt.Sprintf(" | Response time: %.2fms",
 		float64(result.ResponseTime.Nanoseconds())/1000000))
-	
+
 	// Record count
 	details.WriteString(fmt.Sprintf(" | Records found: %d", len(records)))
-	
+	if result.DNSAuthoritative {
+		details.WriteString(" | Authoritative")
+	}
+	if result.DNSSECStatus != "" {
+		details.WriteString(fmt.Sprintf(" | DNSSEC: %s", result.DNSSECStatus))
+		if result.DNSSECReason != "" {
+			details.WriteString(fmt.Sprintf(" (%s)", result.DNSSECReason))
+		}
+	}
+
 	// Show first few records for context
 	if len(records) > 0 {
 		details.WriteString(" | ")
 		if len(records) <= 3 {
 			details.WriteString(fmt.Sprintf("Results: %s", strings.Join(records, ", ")))
 		} else {
-			details.WriteString(fmt.Sprintf("Results: %s... (+%d more)", 
+			details.WriteString(fmt.Sprintf("Results: %s... (+%d more)",
 				strings.Join(records[:3], ", "), len(records)-3))
 		}
 	}
-	
+
 	return details.String()
 }
 
 func (d *DNSOperation) createDetailedErrorMessage(errorMsg, host, queryType string) string {
 	var details strings.Builder
-	
+
 	errorLower := strings.ToLower(errorMsg)
-	
-	if strings.Contains(errorLower, "timeout") {
+
+	if strings.Contains(errorLower, "dnssec validation failed") {
+		details.WriteString("🔐 DNSSEC BOGUS - Signature validation failed")
+	} else if strings.Contains(errorLower, "unexpected dns answer") {
+		details.WriteString("🎯 ANSWER MISMATCH - DNS answer differs from the expected records")
+	} else if strings.Contains(errorLower, "timeout") {
 		details.WriteString("⏱️ DNS TIMEOUT - Query timed out")
-	} else if strings.Contains(errorLower, "no such host") || strings.Contains(errorLower, "host not found") {
+	} else if strings.Contains(errorLower, "canceled") {
+		details.WriteString("🛑 DNS CANCELED - Query canceled")
+	} else if strings.Contains(errorLower, "nxdomain") || strings.Contains(errorLower, "no such host") || strings.Contains(errorLower, "host not found") {
 		details.WriteString("🔍 HOST NOT FOUND - DNS resolution failed")
 	} else if strings.Contains(errorLower, "no answer") || strings.Contains(errorLower, "no records found") {
 		details.WriteString("📝 NO RECORDS - No DNS records of requested type")
-	} else if strings.Contains(errorLower, "server failure") {
+	} else if strings.Contains(errorLower, "servfail") || strings.Contains(errorLower, "server failure") {
 		details.WriteString("🔧 SERVER FAILURE - DNS server error")
 	} else if strings.Contains(errorLower, "refused") {
 		details.WriteString("🚫 QUERY REFUSED - DNS server refused query")
 	} else {
 		details.WriteString("❌ DNS FAILED - Query error")
 	}
-	
+
 	// Add query details
-	details.WriteString(fmt.Sprintf(" | Query: %s %s", 
+	details.WriteString(fmt.Sprintf(" | Query: %s %s",
 		strings.ToUpper(queryType), host))
-	
+
 	// Add specific error details
 	if errorMsg != "" {
 		details.WriteString(fmt.Sprintf(" | Error: %s", d.getShortErrorMessage(errorMsg)))
 	}
-	
+
 	return details.String()
 }
 
@@ -227,28 +510,30 @@ func (d *DNSOperation) getShortErrorMessage(errorMessage string) string {
 	if errorMessage == "" {
 		return "Unknown error"
 	}
-	
+
 	errorLower := strings.ToLower(errorMessage)
-	
-	if strings.Contains(errorLower, "timeout") {
+
+	if strings.HasPrefix(errorLower, "dnssec validation failed: ") {
+		return errorMessage[len("dnssec validation failed: "):] // Keep the whole reason
+	} else if strings.Contains(errorLower, "timeout") {
 		return "Query timeout"
-	} else if strings.Contains(errorLower, "no such host") {
+	} else if strings.Contains(errorLower, "nxdomain") || strings.Contains(errorLower, "no such host") {
 		return "Host not found"
 	} else if strings.Contains(errorLower, "no answer") {
 		return "No records found"
-	} else if strings.Contains(errorLower, "server failure") {
+	} else if strings.Contains(errorLower, "servfail") || strings.Contains(errorLower, "server failure") {
 		return "DNS server failure"
 	} else if strings.Contains(errorLower, "refused") {
 		return "Query refused"
 	} else if strings.Contains(errorLower, "network unreachable") {
 		return "Network unreachable"
 	}
-	
+
 	// For other errors, take first 50 characters and clean it up
 	shortMsg := errorMessage
 	if len(shortMsg) > 50 {
 		shortMsg = shortMsg[:50] + "..."
 	}
-	
+
 	return shortMsg
 }
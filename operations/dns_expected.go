@@ -0,0 +1,111 @@
+package operations
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// How the answers of a DNS check are compared with the expected values
+const (
+	DNSMatchExact    = "exact"    // The answers must be exactly the expected set
+	DNSMatchContains = "contains" // The answers must include every expected value
+)
+
+// matchExpectedAnswers compares the answer records of the queried type with the expected values
+// and describes the difference, or returns "" when they match
+func matchExpectedAnswers(answers []dns.RR, qtype uint16, expected []string, match string) string {
+	if len(expected) == 0 {
+		return ""
+	}
+
+	var records []dns.RR
+	for _, rr := range answers {
+		if rr.Header().Rrtype == qtype {
+			records = append(records, rr)
+		}
+	}
+
+	matched := make([]bool, len(records))
+	var missing []string
+	for _, value := range expected {
+		found := false
+		for i, rr := range records {
+			if answerMatches(rr, value) {
+				matched[i] = true
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, value)
+		}
+	}
+
+	var unexpected []string
+	if match != DNSMatchContains {
+		for i, rr := range records {
+			if !matched[i] {
+				unexpected = append(unexpected, answerValue(rr))
+			}
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+	if len(unexpected) > 0 {
+		problems = append(problems, "unexpected "+strings.Join(unexpected, ", "))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// answerMatches reports whether an expected value matches a record. Addresses are compared
+// as IPs and names case-insensitively with or without the trailing dot; an MX value may be
+// the host alone or "preference host", an SRV value "target" or "target:port", a CAA value
+// "tag value" or "flag tag value" and an SOA value the primary nameserver or the serial.
+// TXT values must match exactly; DS and DNSKEY values are compared in presentation format.
+func answerMatches(rr dns.RR, expected string) bool {
+	expected = strings.TrimSpace(expected)
+
+	switch record := rr.(type) {
+	case *dns.A:
+		ip := net.ParseIP(expected)
+		return ip != nil && ip.Equal(record.A)
+	case *dns.AAAA:
+		ip := net.ParseIP(expected)
+		return ip != nil && ip.Equal(record.AAAA)
+	case *dns.MX:
+		host := normalizeDNSName(record.Mx)
+		value := normalizeDNSName(expected)
+		return value == host || value == fmt.Sprintf("%d %s", record.Preference, host)
+	case *dns.TXT:
+		return expected == strings.Join(record.Txt, "")
+	case *dns.CNAME:
+		return normalizeDNSName(expected) == normalizeDNSName(record.Target)
+	case *dns.NS:
+		return normalizeDNSName(expected) == normalizeDNSName(record.Ns)
+	case *dns.PTR:
+		return normalizeDNSName(expected) == normalizeDNSName(record.Ptr)
+	case *dns.SRV:
+		target := normalizeDNSName(record.Target)
+		if host, port, err := net.SplitHostPort(expected); err == nil {
+			return normalizeDNSName(host) == target && port == fmt.Sprint(record.Port)
+		}
+		return normalizeDNSName(expected) == target
+	case *dns.CAA:
+		value := strings.ReplaceAll(expected, `"`, "")
+		return strings.EqualFold(value, fmt.Sprintf("%s %s", record.Tag, record.Value)) ||
+			strings.EqualFold(value, fmt.Sprintf("%d %s %s", record.Flag, record.Tag, record.Value))
+	case *dns.SOA:
+		return normalizeDNSName(expected) == normalizeDNSName(record.Ns) || expected == fmt.Sprint(record.Serial)
+	}
+	// DS and DNSKEY: digests and keys are case-insensitive, whitespace is not significant
+	return strings.EqualFold(strings.Join(strings.Fields(expected), " "), strings.Join(strings.Fields(answerValue(rr)), " "))
+}
+
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
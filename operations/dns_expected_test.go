@@ -0,0 +1,116 @@
+package operations
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// mustRRs parses records in zone file format
+func mustRRs(t *testing.T, records ...string) []dns.RR {
+	t.Helper()
+	rrs := make([]dns.RR, 0, len(records))
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("invalid record %q: %v", record, err)
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs
+}
+
+func TestMatchExpectedAnswers(t *testing.T) {
+	answers := mustRRs(t,
+		"www.example.com. 300 IN CNAME web.example.com.",
+		"web.example.com. 300 IN A 192.0.2.1",
+		"web.example.com. 300 IN A 192.0.2.2",
+	)
+
+	tests := []struct {
+		name     string
+		expected []string
+		match    string
+		want     string
+	}{
+		{name: "nothing expected", expected: nil, want: ""},
+		{name: "exact set in any order", expected: []string{"192.0.2.2", "192.0.2.1"}, want: ""},
+		{name: "exact with an extra answer", expected: []string{"192.0.2.1"}, want: "unexpected 192.0.2.2"},
+		{name: "exact with a missing answer", expected: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, want: "missing 192.0.2.3"},
+		{name: "missing and unexpected", expected: []string{"192.0.2.1", "198.51.100.7"}, want: "missing 198.51.100.7; unexpected 192.0.2.2"},
+		{name: "contains ignores extra answers", expected: []string{"192.0.2.1"}, match: DNSMatchContains, want: ""},
+		{name: "contains with a missing answer", expected: []string{"192.0.2.9"}, match: DNSMatchContains, want: "missing 192.0.2.9"},
+		{name: "records of other types are ignored", expected: []string{"web.example.com"}, want: "missing web.example.com; unexpected 192.0.2.1, 192.0.2.2"},
+	}
+
+	for _, tt := range tests {
+		if got := matchExpectedAnswers(answers, dns.TypeA, tt.expected, tt.match); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := matchExpectedAnswers(nil, dns.TypeA, []string{"192.0.2.1"}, DNSMatchExact); got != "missing 192.0.2.1" {
+		t.Errorf("empty answer: got %q", got)
+	}
+}
+
+func TestAnswerMatches(t *testing.T) {
+	tests := []struct {
+		record   string
+		expected string
+		want     bool
+	}{
+		{record: "example.com. IN A 192.0.2.1", expected: "192.0.2.1", want: true},
+		{record: "example.com. IN A 192.0.2.1", expected: " 192.0.2.1 ", want: true},
+		{record: "example.com. IN A 192.0.2.1", expected: "192.0.2.10", want: false},
+		{record: "example.com. IN A 192.0.2.1", expected: "not an ip", want: false},
+		{record: "example.com. IN AAAA 2001:db8::1", expected: "2001:0db8:0000::0001", want: true},
+		{record: "example.com. IN AAAA 2001:db8::1", expected: "2001:db8::2", want: false},
+		{record: "example.com. IN MX 10 mail.example.com.", expected: "mail.example.com", want: true},
+		{record: "example.com. IN MX 10 mail.example.com.", expected: "10 Mail.Example.com.", want: true},
+		{record: "example.com. IN MX 10 mail.example.com.", expected: "20 mail.example.com", want: false},
+		{record: `example.com. IN TXT "v=spf1 " "-all"`, expected: "v=spf1 -all", want: true},
+		{record: `example.com. IN TXT "v=spf1 -all"`, expected: "V=SPF1 -ALL", want: false},
+		{record: "www.example.com. IN CNAME web.example.com.", expected: "WEB.example.com", want: true},
+		{record: "www.example.com. IN CNAME web.example.com.", expected: "example.com", want: false},
+		{record: "example.com. IN NS ns1.example.net.", expected: "ns1.example.net.", want: true},
+		{record: "example.com. IN NS ns1.example.net.", expected: "ns2.example.net", want: false},
+		{record: "_sip._tcp.example.com. IN SRV 10 60 5060 sip.example.com.", expected: "sip.example.com", want: true},
+		{record: "_sip._tcp.example.com. IN SRV 10 60 5060 sip.example.com.", expected: "SIP.example.com.:5060", want: true},
+		{record: "_sip._tcp.example.com. IN SRV 10 60 5060 sip.example.com.", expected: "sip.example.com:5061", want: false},
+		{record: `example.com. IN CAA 0 issue "letsencrypt.org"`, expected: "issue letsencrypt.org", want: true},
+		{record: `example.com. IN CAA 0 issue "letsencrypt.org"`, expected: `0 issue "LetsEncrypt.org"`, want: true},
+		{record: `example.com. IN CAA 0 issue "letsencrypt.org"`, expected: "128 issue letsencrypt.org", want: false},
+		{record: `example.com. IN CAA 0 issue "letsencrypt.org"`, expected: "issuewild letsencrypt.org", want: false},
+		{record: "1.2.0.192.in-addr.arpa. IN PTR host.example.com.", expected: "host.example.com", want: true},
+		{record: "1.2.0.192.in-addr.arpa. IN PTR host.example.com.", expected: "other.example.com", want: false},
+		{record: "example.com. IN SOA ns1.example.com. hostmaster.example.com. 2024010101 7200 3600 1209600 300", expected: "ns1.example.com.", want: true},
+		{record: "example.com. IN SOA ns1.example.com. hostmaster.example.com. 2024010101 7200 3600 1209600 300", expected: "2024010101", want: true},
+		{record: "example.com. IN SOA ns1.example.com. hostmaster.example.com. 2024010101 7200 3600 1209600 300", expected: "hostmaster.example.com", want: false},
+		{record: "example.com. IN DS 370 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C", expected: "370 13 2 be74359954660069d5c63d200c39f5603827d7dd02b56f120ee9f3a86764247c", want: true},
+		{record: "example.com. IN DS 370 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C", expected: "370  13 2\tBE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C", want: true},
+		{record: "example.com. IN DS 370 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C", expected: "371 13 2 BE74359954660069D5C63D200C39F5603827D7DD02B56F120EE9F3A86764247C", want: false},
+		{record: "example.com. IN DNSKEY 257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==", expected: "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==", want: true},
+		{record: "example.com. IN DNSKEY 257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==", expected: "256 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==", want: false},
+	}
+
+	for _, tt := range tests {
+		rr := mustRRs(t, tt.record)[0]
+		if got := answerMatches(rr, tt.expected); got != tt.want {
+			t.Errorf("answerMatches(%q, %q) = %v, want %v", tt.record, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDNSQueryType(t *testing.T) {
+	for _, query := range []string{"", "A", "aaaa", "MX", "TXT", "CNAME", "NS", "SRV", "caa", "PTR", "SOA", "DS", "DNSKEY"} {
+		if err := ValidateDNSQueryType(query); err != nil {
+			t.Errorf("ValidateDNSQueryType(%q) = %v, want nil", query, err)
+		}
+	}
+	for _, query := range []string{"ANY", "AXFR", "SPF", "A "} {
+		if err := ValidateDNSQueryType(query); err == nil {
+			t.Errorf("ValidateDNSQueryType(%q) accepted an unsupported type", query)
+		}
+	}
+}
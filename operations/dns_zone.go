@@ -0,0 +1,275 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"service-operation/types"
+)
+
+// OperationDNSZone is the operation type of zone consistency checks
+const OperationDNSZone types.OperationType = "dns-zone"
+
+// DNSZoneOperation checks that every authoritative nameserver of a zone answers for it with the
+// same SOA serial. The NS set and nameserver addresses come from the configured resolver; the
+// SOA is then asked from each nameserver directly.
+type DNSZoneOperation struct {
+	timeout  time.Duration
+	resolver *DNSOperation
+	port     string // Port of the authoritative nameservers
+}
+
+func NewDNSZoneOperation(timeout time.Duration) *DNSZoneOperation {
+	return NewDNSZoneOperationWithOptions(timeout, nil)
+}
+
+// NewDNSZoneOperationWithOptions creates a zone check that looks up the NS set through the
+// nameserver and transport in options
+func NewDNSZoneOperationWithOptions(timeout time.Duration, options *types.DNSOptions) *DNSZoneOperation {
+	var resolverOptions *types.DNSOptions
+	if options != nil {
+		resolverOptions = &types.DNSOptions{DNSServer: options.DNSServer, DNSProtocol: options.DNSProtocol}
+	}
+	return &DNSZoneOperation{
+		timeout:  timeout,
+		resolver: NewDNSOperationWithOptions(timeout, resolverOptions),
+		port:     "53",
+	}
+}
+
+func (z *DNSZoneOperation) Execute(ctx context.Context, zone string) (*types.OperationResult, error) {
+	if zone == "" {
+		return nil, fmt.Errorf("zone cannot be empty")
+	}
+	if err := ValidateDNSOptions(&types.DNSOptions{DNSProtocol: z.resolver.protocol}); err != nil {
+		return nil, err
+	}
+	result := &types.OperationResult{
+		Type:        OperationDNSZone,
+		Host:        zone,
+		DNSType:     "SOA",
+		DNSProtocol: z.resolver.protocol,
+		StartTime:   time.Now(),
+	}
+
+	server, err := z.resolver.serverAddress()
+	if err != nil {
+		result.EndTime = time.Now()
+		result.Error = err.Error()
+		result.Details = fmt.Sprintf("❌ ZONE CHECK FAILED - %s | Error: %s", zone, err.Error())
+		return result, nil
+	}
+	result.DNSServer = server
+
+	if z.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, z.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	servers, err := z.lookupNameservers(ctx, server, dns.Fqdn(zone))
+	if err == nil {
+		result.DNSZoneServers = z.querySerials(ctx, dns.Fqdn(zone), servers)
+	}
+	result.ResponseTime = time.Since(start)
+	result.EndTime = time.Now()
+
+	if err != nil {
+		result.Error = err.Error()
+		result.Details = fmt.Sprintf("❌ ZONE CHECK FAILED - %s | Error: %s", zone, err.Error())
+		return result, nil
+	}
+
+	for name := range servers {
+		result.DNSRecords = append(result.DNSRecords, name)
+	}
+	sort.Strings(result.DNSRecords)
+
+	var failed, serials []string
+	seen := make(map[uint32]bool)
+	for _, server := range result.DNSZoneServers {
+		label := fmt.Sprintf("%s (%s)", server.Nameserver, server.Address)
+		if server.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s %s", label, server.Error))
+			continue
+		}
+		serials = append(serials, fmt.Sprintf("%s %d", label, server.Serial))
+		seen[server.Serial] = true
+	}
+
+	switch {
+	case len(failed) > 0:
+		result.Error = "Nameserver failures: " + strings.Join(failed, "; ")
+		result.Details = fmt.Sprintf("❌ ZONE CHECK FAILED - %s | %d/%d nameservers failed | %s",
+			zone, len(failed), len(result.DNSZoneServers), strings.Join(failed, "; "))
+	case len(seen) > 1:
+		result.Error = "SOA serial mismatch: " + strings.Join(serials, ", ")
+		result.Details = fmt.Sprintf("🔀 SERIAL MISMATCH - %s | %s", zone, strings.Join(serials, ", "))
+	default:
+		result.Success = true
+		result.Details = fmt.Sprintf("🟢 ZONE CONSISTENT - %s | %d nameservers at serial %d | Response time: %.2fms",
+			zone, len(result.DNSZoneServers), result.DNSZoneServers[0].Serial,
+			float64(result.ResponseTime.Nanoseconds())/1000000)
+	}
+
+	return result, nil
+}
+
+// lookupNameservers returns the NS set of the zone with the addresses of each nameserver. IPv6
+// addresses are only used for nameservers without an IPv4 address.
+func (z *DNSZoneOperation) lookupNameservers(ctx context.Context, server, zone string) (map[string][]string, error) {
+	resp, err := z.query(ctx, server, zone, dns.TypeNS)
+	if err != nil {
+		return nil, fmt.Errorf("NS lookup failed: %v", err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("NS lookup failed: %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	servers := make(map[string][]string)
+	// A referral from the parent carries the NS set in the authority section
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns} {
+		for _, rr := range section {
+			if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, zone) {
+				servers[strings.ToLower(ns.Ns)] = nil
+			}
+		}
+		if len(servers) > 0 {
+			break
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no NS records for %s", zone)
+	}
+
+	// Glue records save a lookup per nameserver
+	for _, rr := range resp.Extra {
+		if a, ok := rr.(*dns.A); ok {
+			name := strings.ToLower(a.Hdr.Name)
+			if _, ok := servers[name]; ok {
+				servers[name] = append(servers[name], a.A.String())
+			}
+		}
+	}
+
+	for name, addresses := range servers {
+		if len(addresses) > 0 {
+			continue
+		}
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			addresses, err := z.lookupAddresses(ctx, server, name, qtype)
+			if err != nil {
+				return nil, err
+			}
+			if len(addresses) > 0 {
+				servers[name] = addresses
+				break
+			}
+		}
+	}
+	return servers, nil
+}
+
+func (z *DNSZoneOperation) lookupAddresses(ctx context.Context, server, name string, qtype uint16) ([]string, error) {
+	resp, err := z.query(ctx, server, name, qtype)
+	if err != nil {
+		return nil, fmt.Errorf("%s lookup for nameserver %s failed: %v", dns.TypeToString[qtype], name, err)
+	}
+
+	var addresses []string
+	for _, rr := range resp.Answer {
+		switch record := rr.(type) {
+		case *dns.A:
+			addresses = append(addresses, record.A.String())
+		case *dns.AAAA:
+			addresses = append(addresses, record.AAAA.String())
+		}
+	}
+	return addresses, nil
+}
+
+// querySerials asks every nameserver address for the zone's SOA in parallel. A server that does
+// not answer is unresponsive; one that answers without authority or without the SOA is lame.
+func (z *DNSZoneOperation) querySerials(ctx context.Context, zone string, servers map[string][]string) []types.DNSZoneServer {
+	var results []types.DNSZoneServer
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	direct := NewDNSOperation(z.timeout)
+	for name, addresses := range servers {
+		if len(addresses) == 0 {
+			// Queries of earlier nameservers are already appending
+			mu.Lock()
+			results = append(results, types.DNSZoneServer{Nameserver: name, Error: "unresponsive: no address"})
+			mu.Unlock()
+			continue
+		}
+		for _, address := range addresses {
+			wg.Add(1)
+			go func(name, address string) {
+				defer wg.Done()
+				server := types.DNSZoneServer{Nameserver: name, Address: address}
+
+				msg := new(dns.Msg)
+				msg.SetQuestion(zone, dns.TypeSOA)
+				msg.RecursionDesired = false
+				msg.SetEdns0(dnsUDPSize, false)
+
+				start := time.Now()
+				resp, _, err := direct.exchange(ctx, msg, net.JoinHostPort(address, z.port))
+				server.ResponseTime = time.Since(start)
+
+				switch {
+				case err != nil:
+					server.Error = "unresponsive: " + err.Error()
+				case resp.Rcode != dns.RcodeSuccess:
+					server.Error = "lame: " + dns.RcodeToString[resp.Rcode]
+				case !resp.Authoritative:
+					server.Error = "lame: not authoritative"
+				default:
+					server.Error = "lame: no SOA record"
+					for _, rr := range resp.Answer {
+						if soa, ok := rr.(*dns.SOA); ok && strings.EqualFold(soa.Hdr.Name, zone) {
+							server.Serial = soa.Serial
+							server.Error = ""
+						}
+					}
+				}
+
+				mu.Lock()
+				results = append(results, server)
+				mu.Unlock()
+			}(name, address)
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Nameserver != results[j].Nameserver {
+			return results[i].Nameserver < results[j].Nameserver
+		}
+		return results[i].Address < results[j].Address
+	})
+	return results
+}
+
+// query sends a recursive query to the configured resolver
+func (z *DNSZoneOperation) query(ctx context.Context, server, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.SetEdns0(dnsUDPSize, false)
+
+	resp, _, err := z.resolver.exchange(ctx, msg, server)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,197 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"service-operation/types"
+)
+
+func TestDNSZoneInvalidServerReportsResult(t *testing.T) {
+	op := NewDNSZoneOperationWithOptions(time.Second, &types.DNSOptions{DNSProtocol: DNSProtocolDoH})
+
+	result, err := op.Execute(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Execute returned error %v, want a failed result", err)
+	}
+	if result.Success || result.Type != OperationDNSZone || !strings.Contains(result.Error, "dns_server is required") {
+		t.Errorf("result %+v, want a failed zone check with the server error", result)
+	}
+	if !strings.HasPrefix(result.Details, "❌ ZONE CHECK FAILED - example.com") || result.EndTime.IsZero() {
+		t.Errorf("details %q, end time %v", result.Details, result.EndTime)
+	}
+
+	if _, err := op.Execute(context.Background(), ""); err == nil {
+		t.Error("empty zone accepted")
+	}
+}
+
+// startDNSStub serves handler over UDP on addr until the test ends
+func startDNSStub(t *testing.T, addr string, handler dns.HandlerFunc) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+// testNameserver is an authoritative nameserver of the stub zone
+type testNameserver struct {
+	name    string
+	glue    bool   // Address in the additional section of the NS response, otherwise from an A lookup
+	noAddr  bool   // Neither glue nor an A record
+	down    bool   // Nothing listens on its address
+	serial  uint32 // SOA serial it answers with
+	rcode   int
+	notAuth bool // Answers without the AA flag
+	noSOA   bool // Answers without the SOA record
+	wantErr string
+	address string
+}
+
+func TestDNSZoneConsistency(t *testing.T) {
+	tests := []struct {
+		name        string
+		nameservers []testNameserver
+		wantSuccess bool
+		wantError   string
+	}{
+		{
+			name: "serials match",
+			nameservers: []testNameserver{
+				{name: "ns1", glue: true, serial: 2024010101},
+				{name: "ns2", serial: 2024010101},
+			},
+			wantSuccess: true,
+		},
+		{
+			name: "serial mismatch",
+			nameservers: []testNameserver{
+				{name: "ns1", glue: true, serial: 2024010101},
+				{name: "ns2", glue: true, serial: 2024010102},
+			},
+			wantError: "SOA serial mismatch: ns1.example.com. (127.0.0.2) 2024010101, ns2.example.com. (127.0.0.3) 2024010102",
+		},
+		{
+			name: "lame nameservers",
+			nameservers: []testNameserver{
+				{name: "ns1", glue: true, serial: 7},
+				{name: "ns2", glue: true, serial: 7, notAuth: true, wantErr: "lame: not authoritative"},
+				{name: "ns3", glue: true, rcode: dns.RcodeRefused, wantErr: "lame: REFUSED"},
+				{name: "ns4", serial: 7, noSOA: true, wantErr: "lame: no SOA record"},
+			},
+			wantError: "Nameserver failures: ",
+		},
+		{
+			name: "unresponsive and glue-less nameservers",
+			nameservers: []testNameserver{
+				{name: "ns1", glue: true, serial: 7},
+				{name: "ns2", glue: true, down: true, wantErr: "unresponsive: "},
+				{name: "ns3", noAddr: true, wantErr: "unresponsive: no address"},
+				{name: "ns4", glue: true, serial: 7},
+				{name: "ns5", serial: 7},
+			},
+			wantError: "Nameserver failures: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runZoneStub(t, tt.nameservers)
+
+			if result.Success != tt.wantSuccess || !strings.HasPrefix(result.Error, tt.wantError) || (tt.wantError == "") != (result.Error == "") {
+				t.Fatalf("success %v, error %q, want %v, %q", result.Success, result.Error, tt.wantSuccess, tt.wantError)
+			}
+			if len(result.DNSZoneServers) != len(tt.nameservers) {
+				t.Fatalf("%d nameserver results, want %d: %+v", len(result.DNSZoneServers), len(tt.nameservers), result.DNSZoneServers)
+			}
+			for i, ns := range tt.nameservers {
+				got := result.DNSZoneServers[i]
+				if got.Nameserver != ns.name+".example.com." || !strings.HasPrefix(got.Error, ns.wantErr) || (ns.wantErr == "") != (got.Error == "") {
+					t.Errorf("nameserver %d: %s error %q, want %s.example.com. error %q", i, got.Nameserver, got.Error, ns.name, ns.wantErr)
+				}
+				if ns.wantErr == "" && got.Serial != ns.serial {
+					t.Errorf("%s serial %d, want %d", got.Nameserver, got.Serial, ns.serial)
+				}
+			}
+		})
+	}
+}
+
+// runZoneStub checks example.com against a stub resolver and stub nameservers on loopback
+// addresses that share one port
+func runZoneStub(t *testing.T, nameservers []testNameserver) *types.OperationResult {
+	t.Helper()
+	const zone = "example.com."
+
+	byName := make(map[string]*testNameserver)
+	for i := range nameservers {
+		ns := &nameservers[i]
+		ns.address = fmt.Sprintf("127.0.0.%d", i+2)
+		byName[ns.name+"."+zone] = ns
+	}
+
+	resolver := startDNSStub(t, "127.0.0.1:0", func(w dns.ResponseWriter, r *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(r)
+		q := r.Question[0]
+		switch {
+		case q.Qtype == dns.TypeNS && q.Name == zone:
+			for _, ns := range nameservers {
+				host := ns.name + "." + zone
+				resp.Answer = append(resp.Answer, &dns.NS{Hdr: dns.RR_Header{Name: zone, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 300}, Ns: host})
+				if ns.glue {
+					resp.Extra = append(resp.Extra, &dns.A{Hdr: dns.RR_Header{Name: host, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300}, A: net.ParseIP(ns.address)})
+				}
+			}
+		case q.Qtype == dns.TypeA:
+			if ns, ok := byName[q.Name]; ok && !ns.noAddr {
+				resp.Answer = append(resp.Answer, &dns.A{Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300}, A: net.ParseIP(ns.address)})
+			}
+		}
+		w.WriteMsg(resp)
+	})
+	_, port, _ := net.SplitHostPort(resolver)
+
+	for i := range nameservers {
+		ns := nameservers[i]
+		if ns.down || ns.noAddr {
+			continue
+		}
+		startDNSStub(t, net.JoinHostPort(ns.address, port), func(w dns.ResponseWriter, r *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetRcode(r, ns.rcode)
+			resp.Authoritative = !ns.notAuth
+			if ns.rcode == dns.RcodeSuccess && !ns.noSOA {
+				resp.Answer = append(resp.Answer, &dns.SOA{
+					Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+					Ns:      "ns1." + zone,
+					Mbox:    "hostmaster." + zone,
+					Serial:  ns.serial,
+					Refresh: 7200, Retry: 3600, Expire: 1209600, Minttl: 300,
+				})
+			}
+			w.WriteMsg(resp)
+		})
+	}
+
+	op := NewDNSZoneOperationWithOptions(time.Second, &types.DNSOptions{DNSServer: resolver, DNSProtocol: DNSProtocolUDP})
+	op.port = port
+	result, err := op.Execute(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
@@ -0,0 +1,427 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNSSEC validation outcomes (RFC 4035 section 4.3)
+const (
+	DNSSECSecure        = "secure"        // Signed and the chain of trust from the root validates
+	DNSSECInsecure      = "insecure"      // Provably unsigned, e.g. no DS record at a delegation
+	DNSSECBogus         = "bogus"         // Signed but the signatures or the chain do not validate
+	DNSSECIndeterminate = "indeterminate" // The records needed to decide could not be fetched
+)
+
+// rootTrustAnchors are the DS records of the root zone key signing keys published by IANA
+var rootTrustAnchors = []string{
+	". 86400 IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". 86400 IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// zoneTrust is the validation state of a zone and, when secure, its validated keys
+type zoneTrust struct {
+	status string
+	reason string
+	keys   []*dns.DNSKEY
+}
+
+// dnssecValidator walks the chain of trust from the root for one check. Every lookup goes to the
+// check's nameserver with checking disabled, so a validating resolver hands back bogus records
+// and the failure can be explained instead of ending in SERVFAIL.
+type dnssecValidator struct {
+	op      *DNSOperation
+	server  string
+	now     time.Time
+	zones   map[string]*zoneTrust
+	lookups map[string]*dns.Msg
+}
+
+// validateDNSSEC validates the answer section of a response, or the authority section of a
+// negative response, and returns the status with the reason when it is not secure
+func (d *DNSOperation) validateDNSSEC(ctx context.Context, server string, resp *dns.Msg) (string, string) {
+	v := &dnssecValidator{
+		op:      d,
+		server:  server,
+		now:     time.Now(),
+		zones:   make(map[string]*zoneTrust),
+		lookups: make(map[string]*dns.Msg),
+	}
+
+	section := resp.Answer
+	if len(section) == 0 {
+		section = resp.Ns // Denial of existence: the SOA and NSEC/NSEC3 records must be signed
+	}
+	if len(section) == 0 {
+		return DNSSECIndeterminate, "response has no records to validate"
+	}
+
+	status, reason := DNSSECSecure, ""
+	for _, set := range splitRRsets(section) {
+		setStatus, setReason := v.validateRRset(ctx, set.records, set.sigs)
+		if dnssecSeverity(setStatus) > dnssecSeverity(status) {
+			status, reason = setStatus, setReason
+		}
+	}
+	return status, reason
+}
+
+// validateRRset checks the signatures of one RRset against the validated keys of its signer
+func (v *dnssecValidator) validateRRset(ctx context.Context, rrset []dns.RR, sigs []*dns.RRSIG) (string, string) {
+	header := rrset[0].Header()
+	label := fmt.Sprintf("%s %s", header.Name, dns.TypeToString[header.Rrtype])
+
+	if len(sigs) == 0 {
+		// Unsigned data is only acceptable in a zone that is provably unsigned
+		zone, err := v.zoneOf(ctx, header.Name)
+		if err != nil {
+			return DNSSECIndeterminate, err.Error()
+		}
+		trust := v.trust(ctx, zone)
+		if trust.status == DNSSECSecure {
+			return DNSSECBogus, fmt.Sprintf("%s has no RRSIG although %s is signed", label, zone)
+		}
+		return trust.status, trust.reason
+	}
+
+	signer := dns.CanonicalName(sigs[0].SignerName)
+	if !dns.IsSubDomain(signer, dns.CanonicalName(header.Name)) {
+		return DNSSECBogus, fmt.Sprintf("%s is signed by unrelated zone %s", label, signer)
+	}
+
+	trust := v.trust(ctx, signer)
+	if trust.status != DNSSECSecure {
+		return trust.status, trust.reason
+	}
+	if reason := v.verify(rrset, sigs, trust.keys); reason != "" {
+		return DNSSECBogus, fmt.Sprintf("%s: %s", label, reason)
+	}
+	return DNSSECSecure, ""
+}
+
+// trust returns the validation state of a zone, following DS records up to the root
+func (v *dnssecValidator) trust(ctx context.Context, zone string) *zoneTrust {
+	zone = dns.CanonicalName(zone)
+	if trust, ok := v.zones[zone]; ok {
+		return trust
+	}
+	trust := v.computeTrust(ctx, zone)
+	v.zones[zone] = trust
+	return trust
+}
+
+func (v *dnssecValidator) computeTrust(ctx context.Context, zone string) *zoneTrust {
+	if zone == "." {
+		var anchors []*dns.DS
+		for _, anchor := range rootTrustAnchors {
+			rr, err := dns.NewRR(anchor)
+			if err == nil {
+				anchors = append(anchors, rr.(*dns.DS))
+			}
+		}
+		return v.zoneKeys(ctx, zone, anchors)
+	}
+
+	resp, err := v.lookup(ctx, zone, dns.TypeDS)
+	if err != nil {
+		return &zoneTrust{status: DNSSECIndeterminate, reason: err.Error()}
+	}
+
+	parent := parentZone(resp, zone)
+	if parent == zone || !dns.IsSubDomain(parent, zone) {
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("DS of %s is signed by unrelated zone %s", zone, parent)}
+	}
+	parentTrust := v.trust(ctx, parent)
+	if parentTrust.status != DNSSECSecure {
+		return parentTrust
+	}
+
+	dsSet, sigs := rrsetOf(resp.Answer, zone, dns.TypeDS)
+	if len(dsSet) == 0 {
+		if v.deniesDS(resp, zone, parentTrust.keys) {
+			return &zoneTrust{status: DNSSECInsecure, reason: fmt.Sprintf("%s has no DS record in %s", zone, parent)}
+		}
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("DS record of %s is missing without a signed denial in %s", zone, parent)}
+	}
+	if reason := v.verify(dsSet, sigs, parentTrust.keys); reason != "" {
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("DS of %s: %s", zone, reason)}
+	}
+
+	var anchors []*dns.DS
+	for _, rr := range dsSet {
+		anchors = append(anchors, rr.(*dns.DS))
+	}
+	return v.zoneKeys(ctx, zone, anchors)
+}
+
+// zoneKeys fetches the DNSKEY set of a zone and validates it with the keys matching its DS records
+func (v *dnssecValidator) zoneKeys(ctx context.Context, zone string, dsSet []*dns.DS) *zoneTrust {
+	resp, err := v.lookup(ctx, zone, dns.TypeDNSKEY)
+	if err != nil {
+		return &zoneTrust{status: DNSSECIndeterminate, reason: err.Error()}
+	}
+
+	keySet, sigs := rrsetOf(resp.Answer, zone, dns.TypeDNSKEY)
+	if len(keySet) == 0 {
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("%s has a DS record but no DNSKEY", zone)}
+	}
+
+	var keys, entryKeys []*dns.DNSKEY
+	for _, rr := range keySet {
+		keys = append(keys, rr.(*dns.DNSKEY))
+	}
+	for _, ds := range dsSet {
+		if !supportedDNSSECAlgorithm(ds.Algorithm) {
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != ds.KeyTag || key.Algorithm != ds.Algorithm {
+				continue
+			}
+			if digest := key.ToDS(ds.DigestType); digest != nil && strings.EqualFold(digest.Digest, ds.Digest) {
+				entryKeys = append(entryKeys, key)
+			}
+		}
+	}
+
+	if len(entryKeys) == 0 {
+		if !anySupported(dsSet) {
+			// RFC 4035 5.2: a zone signed only with unknown algorithms is treated as unsigned
+			return &zoneTrust{status: DNSSECInsecure, reason: fmt.Sprintf("%s uses an unsupported DNSSEC algorithm", zone)}
+		}
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("DS mismatch: no DNSKEY of %s matches its DS records", zone)}
+	}
+	if reason := v.verify(keySet, sigs, entryKeys); reason != "" {
+		return &zoneTrust{status: DNSSECBogus, reason: fmt.Sprintf("DNSKEY of %s: %s", zone, reason)}
+	}
+	return &zoneTrust{status: DNSSECSecure, keys: keys}
+}
+
+// deniesDS reports whether the authority section of a DS response holds a signed NSEC or NSEC3
+// record showing that the delegation has no DS record
+func (v *dnssecValidator) deniesDS(resp *dns.Msg, zone string, keys []*dns.DNSKEY) bool {
+	for _, set := range splitRRsets(resp.Ns) {
+		if v.verify(set.records, set.sigs, keys) != "" {
+			continue
+		}
+		for _, rr := range set.records {
+			switch denial := rr.(type) {
+			case *dns.NSEC:
+				if strings.EqualFold(denial.Hdr.Name, zone) && !hasType(denial.TypeBitMap, dns.TypeDS) {
+					return true
+				}
+			case *dns.NSEC3:
+				if denial.Match(zone) && !hasType(denial.TypeBitMap, dns.TypeDS) {
+					return true
+				}
+				if denial.Cover(zone) && denial.Flags&1 == 1 {
+					return true // Opt-out span: unsigned delegations are not listed
+				}
+			}
+		}
+	}
+	return false
+}
+
+// verify checks that at least one signature over rrset validates with one of keys and is
+// within its validity period, and describes the failure otherwise
+func (v *dnssecValidator) verify(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY) string {
+	if len(sigs) == 0 {
+		return "no RRSIG"
+	}
+
+	reason := ""
+	for _, sig := range sigs {
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if err := sig.Verify(key, rrset); err != nil {
+				reason = fmt.Sprintf("RRSIG with key %d does not verify: %v", sig.KeyTag, err)
+				continue
+			}
+			if !sig.ValidityPeriod(v.now) {
+				reason = validityReason(sig, v.now)
+				continue
+			}
+			return ""
+		}
+	}
+
+	if reason == "" {
+		reason = fmt.Sprintf("no DNSKEY matches RRSIG key tag %d", sigs[0].KeyTag)
+	}
+	return reason
+}
+
+// zoneOf returns the zone a name belongs to, from the SOA record of the zone apex
+func (v *dnssecValidator) zoneOf(ctx context.Context, name string) (string, error) {
+	resp, err := v.lookup(ctx, name, dns.TypeSOA)
+	if err != nil {
+		return "", err
+	}
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok && strings.EqualFold(soa.Hdr.Name, name) {
+			return soa.Hdr.Name, nil
+		}
+	}
+	for _, rr := range resp.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Hdr.Name, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find the zone of %s", name)
+}
+
+// lookup queries the check's nameserver with the DO and CD bits set
+func (v *dnssecValidator) lookup(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	key := dns.CanonicalName(name) + " " + dns.TypeToString[qtype]
+	if resp, ok := v.lookups[key]; ok {
+		return resp, nil
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(dnsUDPSize, true)
+	msg.CheckingDisabled = true
+
+	resp, _, err := v.op.exchange(ctx, msg, v.server)
+	if err != nil {
+		return nil, fmt.Errorf("%s lookup failed: %v", key, err)
+	}
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("%s lookup failed: %s", key, dns.RcodeToString[resp.Rcode])
+	}
+	v.lookups[key] = resp
+	return resp, nil
+}
+
+// signedRRset is an RRset of a response section with the signatures covering it
+type signedRRset struct {
+	records []dns.RR
+	sigs    []*dns.RRSIG
+}
+
+// splitRRsets groups a response section by owner and type, leaving out the EDNS0 pseudo-record
+func splitRRsets(section []dns.RR) []*signedRRset {
+	var sets []*signedRRset
+	index := make(map[string]*signedRRset)
+	setFor := func(name string, rrtype uint16) *signedRRset {
+		key := dns.CanonicalName(name) + " " + dns.TypeToString[rrtype]
+		set, ok := index[key]
+		if !ok {
+			set = &signedRRset{}
+			index[key] = set
+			sets = append(sets, set)
+		}
+		return set
+	}
+
+	for _, rr := range section {
+		switch record := rr.(type) {
+		case *dns.OPT:
+		case *dns.RRSIG:
+			set := setFor(record.Hdr.Name, record.TypeCovered)
+			set.sigs = append(set.sigs, record)
+		default:
+			set := setFor(rr.Header().Name, rr.Header().Rrtype)
+			set.records = append(set.records, rr)
+		}
+	}
+
+	// Signatures without the records they cover cannot be validated
+	valid := sets[:0]
+	for _, set := range sets {
+		if len(set.records) > 0 {
+			valid = append(valid, set)
+		}
+	}
+	return valid
+}
+
+// rrsetOf returns the records of a type owned by name and their signatures
+func rrsetOf(section []dns.RR, name string, rrtype uint16) ([]dns.RR, []*dns.RRSIG) {
+	for _, set := range splitRRsets(section) {
+		header := set.records[0].Header()
+		if header.Rrtype == rrtype && strings.EqualFold(header.Name, name) {
+			return set.records, set.sigs
+		}
+	}
+	return nil, nil
+}
+
+// parentZone returns the zone that answered a DS query: the signer of its records, or the owner
+// of the SOA record in an unsigned negative answer
+func parentZone(resp *dns.Msg, zone string) string {
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns} {
+		for _, rr := range section {
+			if sig, ok := rr.(*dns.RRSIG); ok {
+				return dns.CanonicalName(sig.SignerName)
+			}
+		}
+	}
+	for _, rr := range resp.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return dns.CanonicalName(soa.Hdr.Name)
+		}
+	}
+	labels := dns.SplitDomainName(zone)
+	if len(labels) <= 1 {
+		return "."
+	}
+	return dns.Fqdn(strings.Join(labels[1:], "."))
+}
+
+// validityReason describes why a signature is outside its validity period
+func validityReason(sig *dns.RRSIG, now time.Time) string {
+	expiration := time.Unix(int64(sig.Expiration), 0).UTC()
+	if now.After(expiration) {
+		return fmt.Sprintf("RRSIG expired on %s", expiration.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("RRSIG not valid until %s", time.Unix(int64(sig.Inception), 0).UTC().Format(time.RFC3339))
+}
+
+func supportedDNSSECAlgorithm(algorithm uint8) bool {
+	switch algorithm {
+	case dns.RSASHA1, dns.RSASHA1NSEC3SHA1, dns.RSASHA256, dns.RSASHA512,
+		dns.ECDSAP256SHA256, dns.ECDSAP384SHA384, dns.ED25519:
+		return true
+	}
+	return false
+}
+
+// anySupported reports whether any DS record uses an algorithm and digest type this agent can check
+func anySupported(dsSet []*dns.DS) bool {
+	for _, ds := range dsSet {
+		if supportedDNSSECAlgorithm(ds.Algorithm) && (ds.DigestType == dns.SHA1 || ds.DigestType == dns.SHA256 || ds.DigestType == dns.SHA384) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasType(bitmap []uint16, rrtype uint16) bool {
+	for _, t := range bitmap {
+		if t == rrtype {
+			return true
+		}
+	}
+	return false
+}
+
+// dnssecSeverity orders statuses so the worst one of a response is reported
+func dnssecSeverity(status string) int {
+	switch status {
+	case DNSSECInsecure:
+		return 1
+	case DNSSECIndeterminate:
+		return 2
+	case DNSSECBogus:
+		return 3
+	}
+	return 0
+}
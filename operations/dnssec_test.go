@@ -0,0 +1,155 @@
+package operations
+
+import (
+	"crypto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// testZoneKey generates a zone signing key for zone
+func testZoneKey(t *testing.T, zone string) (*dns.DNSKEY, crypto.Signer) {
+	t.Helper()
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     256,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	private, err := key.Generate(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, private.(crypto.Signer)
+}
+
+// testSign signs rrset with key, valid from inception to expiration
+func testSign(t *testing.T, key *dns.DNSKEY, signer crypto.Signer, rrset []dns.RR, inception, expiration time.Time) *dns.RRSIG {
+	t.Helper()
+	sig := &dns.RRSIG{
+		Algorithm:  key.Algorithm,
+		KeyTag:     key.KeyTag(),
+		SignerName: key.Hdr.Name,
+		Inception:  uint32(inception.Unix()),
+		Expiration: uint32(expiration.Unix()),
+	}
+	if err := sig.Sign(signer, rrset); err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestSplitRRsets(t *testing.T) {
+	section := mustRRs(t,
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN RRSIG A 13 2 300 20300101000000 20200101000000 12345 example.com. c2ln",
+		"EXAMPLE.com. 300 IN A 192.0.2.2",
+		"example.com. 300 IN AAAA 2001:db8::1",
+		"example.com. 300 IN RRSIG MX 13 2 300 20300101000000 20200101000000 12345 example.com. c2ln",
+		"www.example.com. 300 IN A 192.0.2.3",
+	)
+	section = append(section, &dns.OPT{Hdr: dns.RR_Header{Name: ".", Rrtype: dns.TypeOPT}})
+
+	sets := splitRRsets(section)
+	want := []struct {
+		owner   string
+		rrtype  uint16
+		records int
+		sigs    int
+	}{
+		{owner: "example.com.", rrtype: dns.TypeA, records: 2, sigs: 1},
+		{owner: "example.com.", rrtype: dns.TypeAAAA, records: 1},
+		{owner: "www.example.com.", rrtype: dns.TypeA, records: 1},
+	}
+	if len(sets) != len(want) {
+		t.Fatalf("%d RRsets, want %d: the MX signature without records and the OPT record are left out", len(sets), len(want))
+	}
+	for i, w := range want {
+		header := sets[i].records[0].Header()
+		if !strings.EqualFold(header.Name, w.owner) || header.Rrtype != w.rrtype || len(sets[i].records) != w.records || len(sets[i].sigs) != w.sigs {
+			t.Errorf("RRset %d: %s %s with %d records and %d signatures, want %s %s with %d and %d", i,
+				header.Name, dns.TypeToString[header.Rrtype], len(sets[i].records), len(sets[i].sigs),
+				w.owner, dns.TypeToString[w.rrtype], w.records, w.sigs)
+		}
+	}
+
+	if records, sigs := rrsetOf(section, "EXAMPLE.COM.", dns.TypeA); len(records) != 2 || len(sigs) != 1 {
+		t.Errorf("rrsetOf found %d records and %d signatures, want 2 and 1", len(records), len(sigs))
+	}
+	if records, _ := rrsetOf(section, "example.com.", dns.TypeMX); records != nil {
+		t.Errorf("rrsetOf returned %d MX records from signatures only", len(records))
+	}
+}
+
+func TestDeniesDS(t *testing.T) {
+	const zone = "child.example.com."
+	key, signer := testZoneKey(t, "example.com.")
+	otherKey, otherSigner := testZoneKey(t, "example.com.")
+	now := time.Now()
+	validFrom, validUntil := now.Add(-time.Hour), now.Add(time.Hour)
+
+	nsec := func(owner string, types ...uint16) dns.RR {
+		return &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: "d.example.com.",
+			TypeBitMap: types,
+		}
+	}
+	nsec3 := func(owner, next string, flags uint8, types ...uint16) dns.RR {
+		return &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: owner + ".example.com.", Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+			Hash:       dns.SHA1,
+			Flags:      flags,
+			SaltLength: 0,
+			HashLength: 20,
+			NextDomain: next,
+			TypeBitMap: types,
+		}
+	}
+	childHash := dns.HashName(zone, dns.SHA1, 0, "")
+	lowest, highest := strings.Repeat("0", 32), strings.Repeat("V", 32)
+
+	tests := []struct {
+		name       string
+		record     dns.RR
+		signer     crypto.Signer
+		signingKey *dns.DNSKEY
+		validUntil time.Time
+		unsigned   bool
+		want       bool
+	}{
+		{name: "NSEC without DS", record: nsec(zone, dns.TypeNS, dns.TypeRRSIG, dns.TypeNSEC), want: true},
+		{name: "NSEC with DS", record: nsec(zone, dns.TypeNS, dns.TypeDS, dns.TypeRRSIG, dns.TypeNSEC), want: false},
+		{name: "NSEC of another name", record: nsec("b.example.com.", dns.TypeNS), want: false},
+		{name: "unsigned NSEC", record: nsec(zone, dns.TypeNS), unsigned: true, want: false},
+		{name: "NSEC signed with an untrusted key", record: nsec(zone, dns.TypeNS), signer: otherSigner, signingKey: otherKey, want: false},
+		{name: "expired NSEC signature", record: nsec(zone, dns.TypeNS), validUntil: now.Add(-time.Minute), want: false},
+		{name: "NSEC3 matching without DS", record: nsec3(childHash, highest, 0, dns.TypeNS), want: true},
+		{name: "NSEC3 matching with DS", record: nsec3(childHash, highest, 0, dns.TypeNS, dns.TypeDS), want: false},
+		{name: "NSEC3 opt-out span", record: nsec3(lowest, highest, 1, dns.TypeNS), want: true},
+		{name: "NSEC3 covering without opt-out", record: nsec3(lowest, highest, 0, dns.TypeNS), want: false},
+	}
+
+	v := &dnssecValidator{now: now}
+	for _, tt := range tests {
+		section := []dns.RR{tt.record}
+		if !tt.unsigned {
+			signingKey, s, until := key, signer, validUntil
+			if tt.signer != nil {
+				signingKey, s = tt.signingKey, tt.signer
+			}
+			if !tt.validUntil.IsZero() {
+				until = tt.validUntil
+			}
+			section = append(section, testSign(t, signingKey, s, section, validFrom, until))
+		}
+
+		resp := new(dns.Msg)
+		resp.Ns = section
+		if got := v.deniesDS(resp, zone, []*dns.DNSKEY{key}); got != tt.want {
+			t.Errorf("%s: deniesDS = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
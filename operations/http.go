@@ -2,6 +2,8 @@
 package operations
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,27 +13,99 @@ import (
 	"service-operation/types"
 )
 
+// defaultMaxRedirects matches the net/http client default
+const defaultMaxRedirects = 10
+
 type HTTPOperation struct {
-	timeout time.Duration
-	client  *http.Client
+	timeout    time.Duration
+	client     *http.Client
+	transport  *http.Transport // Owned by the operation and closed after each check
+	options    *types.HTTPOptions
+	assertions *types.HTTPAssertions
+	body       *bodyAssertions
+	bodyErr    error // Invalid body assertion, reported by Execute
 }
 
 func NewHTTPOperation(timeout time.Duration) *HTTPOperation {
+	// Every check dials a new connection so the DNS, connect and TLS phases are always measured
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
 	return &HTTPOperation{
-		timeout: timeout,
+		timeout:   timeout,
+		transport: transport,
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		},
 	}
 }
 
-func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, error) {
+// NewHTTPOperationWithAssertions creates an HTTP operation that validates the response
+// against status code, keyword, regex and JSON path assertions
+func NewHTTPOperationWithAssertions(timeout time.Duration, assertions *types.HTTPAssertions) *HTTPOperation {
+	op := NewHTTPOperation(timeout)
+	op.assertions = assertions
+	op.body, op.bodyErr = compileBodyAssertions(assertions)
+	return op
+}
+
+// NewHTTPOperationWithOptions creates an HTTP operation with custom headers, body, authentication,
+// redirect policy and TLS verification settings
+func NewHTTPOperationWithOptions(timeout time.Duration, options *types.HTTPOptions, assertions *types.HTTPAssertions) *HTTPOperation {
+	op := NewHTTPOperationWithAssertions(timeout, assertions)
+	if options == nil {
+		return op
+	}
+	op.options = options
+
+	if options.InsecureSkipVerify {
+		op.transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	maxRedirects := options.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+
+	op.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if options.NoFollowRedirects {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+
+	return op
+}
+
+func (h *HTTPOperation) Execute(ctx context.Context, url, method string) (*types.OperationResult, error) {
 	result := &types.OperationResult{
 		Type:       types.OperationHTTP,
 		StartTime:  time.Now(),
 		HTTPMethod: method,
 	}
 
+	var acceptedCodes []statusCodeRange
+	var err error
+	if h.assertions != nil {
+		acceptedCodes, err = parseStatusCodes(h.assertions.StatusCodes)
+		result.HTTPKeyword = strings.Join(h.assertions.Keywords, ",")
+	} else {
+		acceptedCodes, err = parseStatusCodes(defaultStatusCodes)
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("Invalid status code assertion: %v", err)
+		result.EndTime = time.Now()
+		return result, nil
+	}
+	if h.bodyErr != nil {
+		result.Error = fmt.Sprintf("Invalid body assertion: %v", h.bodyErr)
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
 	// Default to GET if no method specified
 	if method == "" {
 		method = "GET"
@@ -45,7 +119,12 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 
 	start := time.Now()
 
-	req, err := http.NewRequest(method, url, nil)
+	var body io.Reader
+	if h.options != nil && h.options.Body != "" {
+		body = strings.NewReader(h.options.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		result.Error = fmt.Sprintf("Failed to create request: %v", err)
 		result.Success = false
@@ -55,6 +134,13 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 
 	// Set a user agent
 	req.Header.Set("User-Agent", "ServiceOperation/1.0")
+	h.applyOptions(req)
+
+	timings := &httpTimings{}
+	req = timings.withTrace(req)
+
+	// Connections are not kept between checks
+	defer h.transport.CloseIdleConnections()
 
 	resp, err := h.client.Do(req)
 	
@@ -62,15 +148,19 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 	result.EndTime = time.Now()
 
 	if err != nil {
+		timings.apply(result)
+
 		// More detailed error messages
-		if strings.Contains(err.Error(), "timeout") {
+		if ctx.Err() == context.Canceled {
+			result.Error = "🛑 Request canceled"
+		} else if strings.Contains(err.Error(), "timeout") {
 			result.Error = fmt.Sprintf("🕐 Request timeout after %.2fs - Server did not respond within the expected time", h.timeout.Seconds())
 		} else if strings.Contains(err.Error(), "connection refused") {
 			result.Error = "🚫 Connection refused - Server is not accepting connections on this port"
 		} else if strings.Contains(err.Error(), "no such host") {
 			result.Error = "🌐 DNS resolution failed - Host not found"
 		} else if strings.Contains(err.Error(), "certificate") {
-			result.Error = "🔒 SSL/TLS certificate error - Certificate verification failed"
+			result.Error = fmt.Sprintf("🔒 SSL/TLS certificate error - %v", err)
 		} else {
 			result.Error = fmt.Sprintf("🔌 Connection error: %v", err)
 		}
@@ -81,7 +171,7 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 
 	result.HTTPStatusCode = resp.StatusCode
 	result.ContentLength = resp.ContentLength
-	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 400
+	result.Success = statusCodeAccepted(acceptedCodes, resp.StatusCode)
 
 	// Capture important headers
 	result.HTTPHeaders = make(map[string]string)
@@ -95,13 +185,34 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 	}
 
 	// Read response body for keyword checking and additional details
-	body, err := io.ReadAll(resp.Body)
-	if err == nil && len(body) > 0 {
-		result.ResponseBody = string(body)
+	respBody, err := io.ReadAll(resp.Body)
+	timings.markBodyDone()
+	timings.apply(result)
+	if err == nil && len(respBody) > 0 {
+		result.ResponseBody = string(respBody)
 		// Update content length if not set by server
 		if result.ContentLength <= 0 {
-			result.ContentLength = int64(len(body))
+			result.ContentLength = int64(len(respBody))
+		}
+	}
+
+	// Body assertions only apply to responses with an accepted status code
+	if result.Success {
+		if err != nil {
+			if h.body.needsBody() {
+				result.FailedAssertion = fmt.Sprintf("could not read response body: %v", err)
+			}
+		} else {
+			result.FailedAssertion = checkBodyAssertions(h.body, respBody)
+		}
+
+		if result.FailedAssertion != "" {
+			result.Success = false
+			result.Error = fmt.Sprintf("🧪 Assertion failed (HTTP %d): %s", resp.StatusCode, result.FailedAssertion)
+			return result, nil
 		}
+	} else if h.assertions != nil && h.assertions.StatusCodes != "" {
+		result.FailedAssertion = fmt.Sprintf("status code %d not in %s", resp.StatusCode, h.assertions.StatusCodes)
 	}
 
 	// Create detailed status message with emoji
@@ -134,3 +245,35 @@ func (h *HTTPOperation) Execute(url, method string) (*types.OperationResult, err
 
 	return result, nil
 }
+
+// applyOptions sets the custom headers, content type and credentials on a request.
+// Custom headers are applied last so they can override the defaults.
+func (h *HTTPOperation) applyOptions(req *http.Request) {
+	if h.options == nil {
+		return
+	}
+
+	if h.options.Body != "" {
+		contentType := h.options.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", contentType)
+	} else if h.options.ContentType != "" {
+		req.Header.Set("Content-Type", h.options.ContentType)
+	}
+
+	if h.options.BasicAuthUser != "" {
+		req.SetBasicAuth(h.options.BasicAuthUser, h.options.BasicAuthPassword)
+	} else if h.options.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+h.options.BearerToken)
+	}
+
+	for key, value := range h.options.Headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+}
@@ -0,0 +1,309 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"service-operation/types"
+)
+
+// defaultStatusCodes is used when a check does not define accepted status codes
+const defaultStatusCodes = "200-399"
+
+// statusCodeRange is an inclusive range of accepted HTTP status codes
+type statusCodeRange struct {
+	min int
+	max int
+}
+
+// parseStatusCodes parses specs like "200,201,300-399" or "2xx,304"
+func parseStatusCodes(spec string) ([]statusCodeRange, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = defaultStatusCodes
+	}
+
+	var ranges []statusCodeRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		switch {
+		case len(part) == 3 && strings.HasSuffix(part, "xx"):
+			class, err := strconv.Atoi(part[:1])
+			if err != nil || class < 1 || class > 5 {
+				return nil, fmt.Errorf("invalid status code class %q", part)
+			}
+			ranges = append(ranges, statusCodeRange{min: class * 100, max: class*100 + 99})
+
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			min, errMin := strconv.Atoi(strings.TrimSpace(bounds[0]))
+			max, errMax := strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if errMin != nil || errMax != nil || min > max {
+				return nil, fmt.Errorf("invalid status code range %q", part)
+			}
+			ranges = append(ranges, statusCodeRange{min: min, max: max})
+
+		default:
+			code, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code %q", part)
+			}
+			ranges = append(ranges, statusCodeRange{min: code, max: code})
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no status codes in %q", spec)
+	}
+	return ranges, nil
+}
+
+func statusCodeAccepted(ranges []statusCodeRange, code int) bool {
+	for _, r := range ranges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateHTTPAssertions checks that status code specs, regexes and JSON paths are well formed
+func ValidateHTTPAssertions(assertions *types.HTTPAssertions) error {
+	if assertions == nil {
+		return nil
+	}
+
+	if _, err := parseStatusCodes(assertions.StatusCodes); err != nil {
+		return err
+	}
+	if _, err := compileBodyAssertions(assertions); err != nil {
+		return err
+	}
+	for _, jp := range assertions.JSONPaths {
+		if _, err := parseJSONPath(jp.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bodyAssertions are the response body checks of an operation, with the regexes compiled once
+type bodyAssertions struct {
+	keywords          []string
+	forbiddenKeywords []string
+	regexes           []*regexp.Regexp
+	forbiddenRegexes  []*regexp.Regexp
+	jsonPaths         []types.JSONPathAssertion
+}
+
+// compileBodyAssertions prepares the body checks of assertions, returning nil when there are none
+func compileBodyAssertions(assertions *types.HTTPAssertions) (*bodyAssertions, error) {
+	if assertions == nil {
+		return nil, nil
+	}
+
+	body := &bodyAssertions{
+		keywords:          assertions.Keywords,
+		forbiddenKeywords: assertions.ForbiddenKeywords,
+		jsonPaths:         assertions.JSONPaths,
+	}
+	for _, pattern := range assertions.Regexes {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %v", pattern, err)
+		}
+		body.regexes = append(body.regexes, re)
+	}
+	for _, pattern := range assertions.ForbiddenRegexes {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %v", pattern, err)
+		}
+		body.forbiddenRegexes = append(body.forbiddenRegexes, re)
+	}
+
+	if !body.needsBody() {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// needsBody reports whether any assertion inspects the response body
+func (b *bodyAssertions) needsBody() bool {
+	return b != nil && (len(b.keywords) > 0 || len(b.forbiddenKeywords) > 0 || len(b.regexes) > 0 ||
+		len(b.forbiddenRegexes) > 0 || len(b.jsonPaths) > 0)
+}
+
+// checkBodyAssertions returns a description of the first failed body assertion, or "" when all pass
+func checkBodyAssertions(assertions *bodyAssertions, body []byte) string {
+	if assertions == nil {
+		return ""
+	}
+
+	text := string(body)
+
+	for _, keyword := range assertions.keywords {
+		if !strings.Contains(text, keyword) {
+			return fmt.Sprintf("keyword %q not found in response body", keyword)
+		}
+	}
+
+	for _, keyword := range assertions.forbiddenKeywords {
+		if strings.Contains(text, keyword) {
+			return fmt.Sprintf("forbidden keyword %q found in response body", keyword)
+		}
+	}
+
+	for _, re := range assertions.regexes {
+		if !re.Match(body) {
+			return fmt.Sprintf("regex %q did not match response body", re.String())
+		}
+	}
+
+	for _, re := range assertions.forbiddenRegexes {
+		if re.Match(body) {
+			return fmt.Sprintf("forbidden regex %q matched response body", re.String())
+		}
+	}
+
+	if len(assertions.jsonPaths) > 0 {
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Sprintf("response body is not valid JSON: %v", err)
+		}
+
+		for _, jp := range assertions.jsonPaths {
+			value, err := evaluateJSONPath(doc, jp.Path)
+			if err != nil {
+				return fmt.Sprintf("JSON path %s: %v", jp.Path, err)
+			}
+			if actual := formatJSONValue(value); actual != jp.Expected {
+				return fmt.Sprintf("JSON path %s is %q, expected %q", jp.Path, actual, jp.Expected)
+			}
+		}
+	}
+
+	return ""
+}
+
+// jsonPathSegment is either an object key or an array index
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath supports the dotted subset of JSONPath: $.a.b[0]["c d"]
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+
+	var segments []jsonPathSegment
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSON path %q: empty key", path)
+			}
+			segments = append(segments, jsonPathSegment{key: p[:end]})
+			p = p[end:]
+
+		case '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSON path %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				segments = append(segments, jsonPathSegment{key: unquoted})
+			} else if strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2 {
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			} else if index, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("invalid JSON path %q: bad subscript %q", path, inner)
+			}
+
+		default:
+			// Allow paths without the leading "$." such as "data.status"
+			if len(segments) == 0 && !strings.HasPrefix(path, "$") {
+				p = "." + p
+				continue
+			}
+			return nil, fmt.Errorf("invalid JSON path %q", path)
+		}
+	}
+
+	return segments, nil
+}
+
+func evaluateJSONPath(doc interface{}, path string) (interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, segment := range segments {
+		if segment.isIndex {
+			items, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("not an array at [%d]", segment.index)
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(items)
+			}
+			if index < 0 || index >= len(items) {
+				return nil, fmt.Errorf("index %d out of range", segment.index)
+			}
+			current = items[index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("not an object at %q", segment.key)
+		}
+		value, exists := object[segment.key]
+		if !exists {
+			return nil, fmt.Errorf("key %q not found", segment.key)
+		}
+		current = value
+	}
+
+	return current, nil
+}
+
+// formatJSONValue renders a decoded JSON value the way users write it in an expectation
+func formatJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
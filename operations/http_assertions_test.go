@@ -0,0 +1,189 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"service-operation/types"
+)
+
+func TestParseStatusCodes(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []statusCodeRange
+		wantErr bool
+	}{
+		{spec: "", want: []statusCodeRange{{200, 399}}},
+		{spec: "200", want: []statusCodeRange{{200, 200}}},
+		{spec: "200, 201,204", want: []statusCodeRange{{200, 200}, {201, 201}, {204, 204}}},
+		{spec: "2xx,304", want: []statusCodeRange{{200, 299}, {304, 304}}},
+		{spec: "2XX", want: []statusCodeRange{{200, 299}}},
+		{spec: "300-399", want: []statusCodeRange{{300, 399}}},
+		{spec: "200,", want: []statusCodeRange{{200, 200}}},
+		{spec: "6xx", wantErr: true},
+		{spec: "0xx", wantErr: true},
+		{spec: "399-300", wantErr: true},
+		{spec: "200-abc", wantErr: true},
+		{spec: "ok", wantErr: true},
+		{spec: ",", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStatusCodes(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseStatusCodes(%q) = %v, want error", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseStatusCodes(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseStatusCodes(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestStatusCodeAccepted(t *testing.T) {
+	ranges, err := parseStatusCodes("2xx,304")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for code, want := range map[int]bool{199: false, 200: true, 299: true, 300: false, 304: true, 500: false} {
+		if got := statusCodeAccepted(ranges, code); got != want {
+			t.Errorf("statusCodeAccepted(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []jsonPathSegment
+		wantErr bool
+	}{
+		{path: "$.status", want: []jsonPathSegment{{key: "status"}}},
+		{path: "status", want: []jsonPathSegment{{key: "status"}}},
+		{path: "data.items[0].name", want: []jsonPathSegment{{key: "data"}, {key: "items"}, {index: 0, isIndex: true}, {key: "name"}}},
+		{path: `$["a b"]['c']`, want: []jsonPathSegment{{key: "a b"}, {key: "c"}}},
+		{path: "$.items[-1]", want: []jsonPathSegment{{key: "items"}, {index: -1, isIndex: true}}},
+		{path: "$", want: nil},
+		{path: "$..a", wantErr: true},
+		{path: "$.items[0", wantErr: true},
+		{path: "$.items[x]", wantErr: true},
+		{path: "$status", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseJSONPath(tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseJSONPath(%q) = %v, want error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseJSONPath(%q) error: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBodyAssertions(t *testing.T) {
+	body := []byte(`{"status":"ok","count":3,"healthy":true,"items":[{"name":"a"},{"name":"b"}],"meta":null}`)
+
+	tests := []struct {
+		name       string
+		assertions *types.HTTPAssertions
+		want       string // Substring of the failure, "" when the assertions pass
+	}{
+		{name: "none", assertions: nil},
+		{name: "keyword", assertions: &types.HTTPAssertions{Keywords: []string{`"status":"ok"`}}},
+		{name: "missing keyword", assertions: &types.HTTPAssertions{Keywords: []string{"down"}}, want: `keyword "down" not found`},
+		{name: "forbidden keyword", assertions: &types.HTTPAssertions{ForbiddenKeywords: []string{"healthy"}}, want: `forbidden keyword "healthy"`},
+		{name: "regex", assertions: &types.HTTPAssertions{Regexes: []string{`"count":\d+`}}},
+		{name: "regex mismatch", assertions: &types.HTTPAssertions{Regexes: []string{`"count":"\d+"`}}, want: "did not match"},
+		{name: "forbidden regex", assertions: &types.HTTPAssertions{ForbiddenRegexes: []string{`(?i)STATUS`}}, want: "forbidden regex"},
+		{name: "json string", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.status", Expected: "ok"}}}},
+		{name: "json number", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "count", Expected: "3"}}}},
+		{name: "json bool", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.healthy", Expected: "true"}}}},
+		{name: "json null", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.meta", Expected: "null"}}}},
+		{name: "json index", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.items[-1].name", Expected: "b"}}}},
+		{name: "json object", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.items[0]", Expected: `{"name":"a"}`}}}},
+		{name: "json mismatch", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.status", Expected: "down"}}}, want: `is "ok", expected "down"`},
+		{name: "json missing key", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.version", Expected: "1"}}}, want: `key "version" not found`},
+		{name: "json out of range", assertions: &types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.items[2]", Expected: "x"}}}, want: "out of range"},
+		{name: "first failure wins", assertions: &types.HTTPAssertions{Keywords: []string{"ok", "missing"}, ForbiddenKeywords: []string{"status"}}, want: `keyword "missing"`},
+	}
+
+	for _, tt := range tests {
+		compiled, err := compileBodyAssertions(tt.assertions)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		got := checkBodyAssertions(compiled, body)
+		if tt.want == "" && got != "" {
+			t.Errorf("%s: unexpected failure %q", tt.name, got)
+		}
+		if tt.want != "" && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want failure containing %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBodyAssertionsInvalidJSON(t *testing.T) {
+	compiled, err := compileBodyAssertions(&types.HTTPAssertions{JSONPaths: []types.JSONPathAssertion{{Path: "$.a", Expected: "1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := checkBodyAssertions(compiled, []byte("<html>")); !strings.Contains(got, "not valid JSON") {
+		t.Errorf("got %q, want invalid JSON failure", got)
+	}
+}
+
+func TestCompileBodyAssertions(t *testing.T) {
+	if _, err := compileBodyAssertions(&types.HTTPAssertions{ForbiddenRegexes: []string{"("}}); err == nil {
+		t.Error("invalid forbidden regex compiled")
+	}
+
+	compiled, err := compileBodyAssertions(&types.HTTPAssertions{StatusCodes: "2xx"})
+	if err != nil || compiled.needsBody() {
+		t.Errorf("status code only assertions need the body: %v, %v", compiled, err)
+	}
+
+	compiled, err = compileBodyAssertions(&types.HTTPAssertions{ForbiddenKeywords: []string{"error"}})
+	if err != nil || !compiled.needsBody() {
+		t.Errorf("forbidden keyword assertions do not need the body: %v, %v", compiled, err)
+	}
+}
+
+func TestForbiddenAssertionsFailOnUnreadableBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more than is sent, so reading the body fails
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+	}))
+	defer server.Close()
+
+	op := NewHTTPOperationWithAssertions(5*time.Second, &types.HTTPAssertions{ForbiddenKeywords: []string{"error"}})
+	result, err := op.Execute(context.Background(), server.URL, "GET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success || !strings.Contains(result.FailedAssertion, "could not read response body") {
+		t.Errorf("success %v, failed assertion %q", result.Success, result.FailedAssertion)
+	}
+}
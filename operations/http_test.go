@@ -0,0 +1,50 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-operation/pocketbase"
+	"service-operation/types"
+)
+
+func TestHTTPRedirectPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// A service record without the field set, as PocketBase returns it
+	var service pocketbase.Service
+	if err := json.Unmarshal([]byte(`{"no_follow_redirects": false, "max_redirects": 0}`), &service); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options *types.HTTPOptions
+		want    int
+	}{
+		{name: "no options", options: nil, want: http.StatusOK},
+		{name: "unset in the service record", options: &types.HTTPOptions{NoFollowRedirects: service.NoFollowRedirects}, want: http.StatusOK},
+		{name: "redirects disabled", options: &types.HTTPOptions{NoFollowRedirects: true}, want: http.StatusMovedPermanently},
+	}
+
+	for _, tt := range tests {
+		result, err := NewHTTPOperationWithOptions(5*time.Second, tt.options, nil).Execute(context.Background(), server.URL+"/old", "GET")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if result.HTTPStatusCode != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, result.HTTPStatusCode, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"service-operation/types"
+)
+
+// httpTimings records the phases of an HTTP request through httptrace.
+// When redirects are followed the phases of the final hop are kept.
+type httpTimings struct {
+	mu sync.Mutex
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest              time.Time
+	firstByte                 time.Time
+	bodyDone                  time.Time
+	connReused                bool
+}
+
+// withTrace attaches a client trace that fills the timings to the request
+func (t *httpTimings) withTrace(req *http.Request) *http.Request {
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart: func(network, addr string) {
+			t.mark(&t.connectStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.mark(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.mark(&t.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.connReused = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() {
+			t.mark(&t.firstByte)
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+func (t *httpTimings) mark(field *time.Time) {
+	t.mu.Lock()
+	*field = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *httpTimings) markBodyDone() {
+	t.mark(&t.bodyDone)
+}
+
+// apply copies the measured phase durations into the result. Time to first byte runs from the
+// request being written, so it is the server's processing time without the connection setup.
+func (t *httpTimings) apply(result *types.OperationResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result.HTTPDNSLookup = phaseDuration(t.dnsStart, t.dnsDone)
+	result.HTTPTCPConnect = phaseDuration(t.connectStart, t.connectDone)
+	result.HTTPTLSHandshake = phaseDuration(t.tlsStart, t.tlsDone)
+	result.HTTPTimeToFirstByte = phaseDuration(t.wroteRequest, t.firstByte)
+	result.HTTPContentTransfer = phaseDuration(t.firstByte, t.bodyDone)
+	result.HTTPConnReused = t.connReused
+}
+
+func phaseDuration(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
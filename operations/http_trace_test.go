@@ -0,0 +1,29 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPTimingsMeasuredOnEveryCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		result, err := NewHTTPOperation(5*time.Second).Execute(context.Background(), server.URL, "GET")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Success {
+			t.Fatalf("check %d failed: %s", i, result.Error)
+		}
+		if result.HTTPTCPConnect <= 0 || result.HTTPConnReused {
+			t.Errorf("check %d: connect %v, reused %v, want a new connection", i, result.HTTPTCPConnect, result.HTTPConnReused)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -24,24 +25,33 @@ func NewPingOperation(timeout time.Duration) *PingOperation {
 	return &PingOperation{timeout: timeout}
 }
 
-func (p *PingOperation) Execute(host string, count int) (*types.OperationResult, error) {
+// protocolICMP is the IANA protocol number of ICMP for IPv4
+const protocolICMP = 1
+
+func (p *PingOperation) Execute(ctx context.Context, host string, count int) (*types.OperationResult, error) {
 	// Validate host/IP
 	if host == "" {
 		return nil, fmt.Errorf("host cannot be empty")
 	}
 
 	// Always try system ping first for better reliability
-	result, err := p.executeSystemPing(host, count)
+	result, err := p.executeSystemPing(ctx, host, count)
 	if err == nil && result.PacketsRecv > 0 {
 		return result, nil
 	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("ping canceled: %w", ctx.Err())
+	}
 	
 	// If system ping fails, try raw ICMP as fallback
 	fmt.Printf("System ping failed (%v), trying raw ICMP\n", err)
-	rawResult, rawErr := p.executeRawICMP(host, count)
+	rawResult, rawErr := p.executeRawICMP(ctx, host, count)
 	if rawErr == nil && rawResult.PacketsRecv > 0 {
 		return rawResult, nil
 	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("ping canceled: %w", ctx.Err())
+	}
 	
 	// If both fail, return the system ping result with error info
 	if result != nil {
@@ -51,7 +61,7 @@ func (p *PingOperation) Execute(host string, count int) (*types.OperationResult,
 	return nil, fmt.Errorf("both system ping and raw ICMP failed: system_err=%v, raw_err=%v", err, rawErr)
 }
 
-func (p *PingOperation) executeSystemPing(host string, count int) (*types.OperationResult, error) {
+func (p *PingOperation) executeSystemPing(ctx context.Context, host string, count int) (*types.OperationResult, error) {
 	result := &types.OperationResult{
 		Type:        types.OperationPing,
 		Host:        host,
@@ -60,7 +70,7 @@ func (p *PingOperation) executeSystemPing(host string, count int) (*types.Operat
 	}
 
 	// Resolve host to get IP address for better details
-	ips, err := net.LookupIP(host)
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
 	var resolvedIP string
 	if err == nil && len(ips) > 0 {
 		resolvedIP = ips[0].String()
@@ -73,70 +83,63 @@ func (p *PingOperation) executeSystemPing(host string, count int) (*types.Operat
 		timeoutSeconds = 10 // Minimum 10 seconds timeout
 	}
 
+	// Set command timeout slightly longer than ping timeout; the process is killed when it
+	// expires or when ctx is canceled
+	cmdTimeout := time.Duration(timeoutSeconds+5) * time.Second
+	cmdCtx, cancel := context.WithTimeout(ctx, cmdTimeout)
+	defer cancel()
+
 	switch runtime.GOOS {
 	case "linux":
 		// Linux ping: -c count -W timeout_in_seconds
-		cmd = exec.Command("ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds), host)
+		cmd = exec.CommandContext(cmdCtx, "ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds), host)
 	case "darwin":
 		// macOS ping: -c count -W timeout_in_milliseconds
-		cmd = exec.Command("ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds*1000), host)
+		cmd = exec.CommandContext(cmdCtx, "ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds*1000), host)
 	case "windows":
 		// Windows ping: -n count -w timeout_in_milliseconds
-		cmd = exec.Command("ping", "-n", fmt.Sprintf("%d", count), "-w", fmt.Sprintf("%d", timeoutSeconds*1000), host)
+		cmd = exec.CommandContext(cmdCtx, "ping", "-n", fmt.Sprintf("%d", count), "-w", fmt.Sprintf("%d", timeoutSeconds*1000), host)
 	default:
 		// Default to Linux-style
-		cmd = exec.Command("ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds), host)
+		cmd = exec.CommandContext(cmdCtx, "ping", "-c", fmt.Sprintf("%d", count), "-W", fmt.Sprintf("%d", timeoutSeconds), host)
 	}
 
-	// Set command timeout slightly longer than ping timeout
-	cmdTimeout := time.Duration(timeoutSeconds+5) * time.Second
-	done := make(chan error, 1)
-	var output []byte
-	var cmdErr error
-
-	go func() {
-		output, cmdErr = cmd.Output()
-		done <- cmdErr
-	}()
-
-	select {
-	case err := <-done:
-		result.EndTime = time.Now()
-		
-		if err != nil {
-			// Even if command fails, try to parse partial output
-			result.PacketLoss = 100.0
-			if len(output) > 0 {
-				p.parseSystemPingOutput(result, string(output), count)
-			}
-			// Set detailed error information
-			result.Error = p.createDetailedErrorMessage(err.Error(), host, resolvedIP)
-			return result, nil // Don't return error, return result with failure info
-		}
-		
-		// Parse successful output
-		if len(output) > 0 {
-			p.parseSystemPingOutput(result, string(output), count)
-		}
+	output, err := cmd.Output()
+	result.EndTime = time.Now()
 
-		// Create detailed success message
-		if result.Success {
-			result.Details = p.createDetailedSuccessMessage(result, host, resolvedIP)
-		}
-		
-		return result, nil
-		
-	case <-time.After(cmdTimeout):
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("ping canceled: %w", ctx.Err())
+	}
+	if cmdCtx.Err() == context.DeadlineExceeded {
 		// Command timed out
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
-		result.EndTime = time.Now()
 		result.PacketLoss = 100.0
 		result.Error = fmt.Sprintf("Ping request timed out after %v", cmdTimeout)
 		result.Details = p.createDetailedErrorMessage("timeout", host, resolvedIP)
 		return result, fmt.Errorf("ping command timed out after %v", cmdTimeout)
 	}
+
+	if err != nil {
+		// Even if command fails, try to parse partial output
+		result.PacketLoss = 100.0
+		if len(output) > 0 {
+			p.parseSystemPingOutput(result, string(output), count)
+		}
+		// Set detailed error information
+		result.Error = p.createDetailedErrorMessage(err.Error(), host, resolvedIP)
+		return result, nil // Don't return error, return result with failure info
+	}
+
+	// Parse successful output
+	if len(output) > 0 {
+		p.parseSystemPingOutput(result, string(output), count)
+	}
+
+	// Create detailed success message
+	if result.Success {
+		result.Details = p.createDetailedSuccessMessage(result, host, resolvedIP)
+	}
+
+	return result, nil
 }
 
 func (p *PingOperation) createDetailedSuccessMessage(result *types.OperationResult, host, resolvedIP string) string {
@@ -348,11 +351,12 @@ func (p *PingOperation) parseWindowsPingOutput(result *types.OperationResult, ou
 	}
 }
 
-func (p *PingOperation) executeRawICMP(host string, count int) (*types.OperationResult, error) {
-	dst, err := net.ResolveIPAddr("ip4", host)
+func (p *PingOperation) executeRawICMP(ctx context.Context, host string, count int) (*types.OperationResult, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve host %s: %v", host, err)
 	}
+	dst := &net.IPAddr{IP: ips[0]}
 
 	// Try to create ICMP connection with better error handling
 	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
@@ -361,6 +365,10 @@ func (p *PingOperation) executeRawICMP(host string, count int) (*types.Operation
 	}
 	defer conn.Close()
 
+	// Closing the connection unblocks a pending read when ctx is canceled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
 	result := &types.OperationResult{
 		Type:        types.OperationPing,
 		Host:        host,
@@ -373,6 +381,10 @@ func (p *PingOperation) executeRawICMP(host string, count int) (*types.Operation
 	pid := os.Getpid() & 0xffff
 
 	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ping canceled: %w", ctx.Err())
+		}
+
 		message := &icmp.Message{
 			Type: ipv4.ICMPTypeEcho,
 			Code: 0,
@@ -401,15 +413,14 @@ func (p *PingOperation) executeRawICMP(host string, count int) (*types.Operation
 		}
 
 		reply := make([]byte, 1500)
-		_, peer, err := conn.ReadFrom(reply)
+		n, peer, err := conn.ReadFrom(reply)
 		if err != nil {
 			continue
 		}
 
 		rtt := time.Since(start)
 
-		// Parse the reply with better error handling - fix the type conversion
-		rm, err := icmp.ParseMessage(int(ipv4.ICMPTypeEchoReply), reply)
+		rm, err := icmp.ParseMessage(protocolICMP, reply[:n])
 		if err != nil {
 			continue
 		}
@@ -436,7 +447,10 @@ func (p *PingOperation) executeRawICMP(host string, count int) (*types.Operation
 
 		// Sleep between pings except for the last one
 		if i < count-1 {
-			time.Sleep(1 * time.Second)
+			select {
+			case <-time.After(1 * time.Second):
+			case <-ctx.Done():
+			}
 		}
 	}
 
@@ -2,8 +2,10 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"service-operation/types"
@@ -17,7 +19,7 @@ func NewTCPOperation(timeout time.Duration) *TCPOperation {
 	return &TCPOperation{timeout: timeout}
 }
 
-func (t *TCPOperation) Execute(host string, port int) (*types.OperationResult, error) {
+func (t *TCPOperation) Execute(ctx context.Context, host string, port int) (*types.OperationResult, error) {
 	result := &types.OperationResult{
 		Type:      types.OperationTCP,
 		Host:      host,
@@ -27,8 +29,9 @@ func (t *TCPOperation) Execute(host string, port int) (*types.OperationResult, e
 
 	start := time.Now()
 	
-	address := fmt.Sprintf("%s:%d", host, port)
-	conn, err := net.DialTimeout("tcp", address, t.timeout)
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: t.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
 	
 	result.ResponseTime = time.Since(start)
 	result.EndTime = time.Now()
@@ -0,0 +1,180 @@
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-operation/types"
+)
+
+// DefaultTLSWarningDays is used when a check does not configure its own warning threshold
+const DefaultTLSWarningDays = 30
+
+type TLSOperation struct {
+	timeout     time.Duration
+	warningDays int
+	downDays    int
+	serverName  string
+}
+
+func NewTLSOperation(timeout time.Duration) *TLSOperation {
+	return &TLSOperation{
+		timeout:     timeout,
+		warningDays: DefaultTLSWarningDays,
+	}
+}
+
+// NewTLSOperationWithThresholds creates a TLS operation that reports a warning when fewer than
+// warningDays are left and fails when fewer than downDays are left
+func NewTLSOperationWithThresholds(timeout time.Duration, warningDays, downDays int, serverName string) *TLSOperation {
+	op := NewTLSOperation(timeout)
+	if warningDays > 0 {
+		op.warningDays = warningDays
+	}
+	if downDays > 0 {
+		op.downDays = downDays
+	}
+	op.serverName = serverName
+	return op
+}
+
+func (t *TLSOperation) Execute(ctx context.Context, host string, port int) (*types.OperationResult, error) {
+	if host == "" {
+		return nil, fmt.Errorf("host cannot be empty")
+	}
+	if port <= 0 {
+		port = 443
+	}
+
+	serverName := t.serverName
+	if serverName == "" {
+		serverName = host
+	}
+
+	result := &types.OperationResult{
+		Type:      types.OperationTLS,
+		Host:      host,
+		Port:      port,
+		StartTime: time.Now(),
+	}
+
+	start := time.Now()
+
+	// Verification is done manually after the handshake so that we can still
+	// report on certificates that are expired or fail chain validation
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: t.timeout},
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+		},
+	}
+	rawConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+
+	result.ResponseTime = time.Since(start)
+	result.EndTime = time.Now()
+
+	if err != nil {
+		result.Error = fmt.Sprintf("🔒 TLS handshake failed: %v", err)
+		result.Details = fmt.Sprintf("❌ TLS FAILED - Handshake with %s:%d failed | Error: %s", host, port, err.Error())
+		return result, nil
+	}
+	conn := rawConn.(*tls.Conn)
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		result.Error = "🔒 Server did not present a certificate"
+		result.Details = fmt.Sprintf("❌ TLS FAILED - No certificate from %s:%d", host, port)
+		return result, nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	notBefore := leaf.NotBefore
+	notAfter := leaf.NotAfter
+
+	result.TLSVersion = tls.VersionName(state.Version)
+	result.TLSCipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	result.TLSSubject = leaf.Subject.String()
+	result.TLSIssuer = leaf.Issuer.String()
+	result.TLSSANs = certificateSANs(leaf)
+	result.TLSNotBefore = &notBefore
+	result.TLSNotAfter = &notAfter
+	daysLeft := int(math.Floor(time.Until(notAfter).Hours() / 24))
+	result.TLSDaysLeft = &daysLeft
+
+	if err := verifyChain(state.PeerCertificates, serverName); err != nil {
+		result.TLSChainError = err.Error()
+	} else {
+		result.TLSChainValid = true
+	}
+
+	switch {
+	case !result.TLSChainValid:
+		result.Error = fmt.Sprintf("🔒 Certificate validation failed: %s", result.TLSChainError)
+	case daysLeft < t.downDays:
+		result.Error = fmt.Sprintf("⏰ Certificate expires in %d days (down threshold: %d days)", daysLeft, t.downDays)
+	default:
+		result.Success = true
+		if daysLeft < t.warningDays {
+			result.Warning = true
+			result.Error = fmt.Sprintf("⚠️ Certificate expires in %d days (warning threshold: %d days)", daysLeft, t.warningDays)
+		}
+	}
+
+	result.Details = t.createDetailsMessage(result)
+
+	return result, nil
+}
+
+// verifyChain validates the presented chain against the system roots and the expected server name
+func verifyChain(certs []*x509.Certificate, serverName string) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+func (t *TLSOperation) createDetailsMessage(result *types.OperationResult) string {
+	var details strings.Builder
+
+	switch {
+	case !result.Success:
+		details.WriteString("❌ TLS FAILED")
+	case result.Warning:
+		details.WriteString("🟡 TLS WARNING")
+	default:
+		details.WriteString("🟢 TLS SUCCESS")
+	}
+
+	details.WriteString(fmt.Sprintf(" - %s:%d | %s %s", result.Host, result.Port, result.TLSVersion, result.TLSCipherSuite))
+	details.WriteString(fmt.Sprintf(" | Expires in %d days", *result.TLSDaysLeft))
+	details.WriteString(fmt.Sprintf(" | Issuer: %s", result.TLSIssuer))
+
+	if result.TLSChainError != "" {
+		details.WriteString(fmt.Sprintf(" | Chain: %s", result.TLSChainError))
+	}
+
+	return details.String()
+}
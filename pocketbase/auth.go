@@ -0,0 +1,284 @@
+package pocketbase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers carrying the agent identity in token mode. PocketBase exposes them to API rules as
+// @request.headers.x_agent_id and @request.headers.x_agent_token.
+const (
+	AgentIDHeader    = "X-Agent-Id"
+	AgentTokenHeader = "X-Agent-Token"
+)
+
+// tokenRefreshMargin is how long before expiry an auth record token is refreshed
+const tokenRefreshMargin = 5 * time.Minute
+
+// AuthConfig holds the credentials the agent uses against PocketBase. With Collection,
+// Identity and Password set the agent signs in as an auth record; AgentID and AgentToken
+// are sent on every request so API rules can match them against regional_service.
+type AuthConfig struct {
+	Collection string
+	Identity   string
+	Password   string
+	AgentID    string
+	AgentToken string
+}
+
+func (a AuthConfig) usesRecordAuth() bool {
+	return a.Collection != "" && a.Identity != "" && a.Password != ""
+}
+
+// authTransport adds credentials to every request and re-authenticates when a token is rejected
+type authTransport struct {
+	baseURL string
+	base    http.RoundTripper
+
+	mu      sync.Mutex
+	config  AuthConfig
+	token   string
+	expires time.Time
+	login   *authAttempt // Sign-in or refresh in progress, shared by concurrent requests
+}
+
+// authAttempt is a sign-in or refresh; token and err are set before done is closed
+type authAttempt struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newAuthTransport(baseURL string, config AuthConfig) *authTransport {
+	return &authTransport{
+		baseURL: baseURL,
+		base:    http.DefaultTransport,
+		config:  config,
+	}
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authReq, token, err := t.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(authReq)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || token == "" {
+		return resp, err
+	}
+
+	// The token was rejected (revoked, or the record's password changed): sign in again once
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	t.invalidate(token)
+
+	retryReq, _, err := t.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		if retryReq.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retryReq)
+}
+
+// authorize returns a copy of req carrying the agent credentials and the record token used
+func (t *authTransport) authorize(req *http.Request) (*http.Request, string, error) {
+	authReq := req.Clone(req.Context())
+
+	t.mu.Lock()
+	agentID, agentToken := t.config.AgentID, t.config.AgentToken
+	t.mu.Unlock()
+
+	if agentID != "" {
+		authReq.Header.Set(AgentIDHeader, agentID)
+	}
+	if agentToken != "" {
+		authReq.Header.Set(AgentTokenHeader, agentToken)
+	}
+
+	token, err := t.currentToken(req.Context())
+	if err != nil {
+		return nil, "", fmt.Errorf("pocketbase authentication failed: %w", err)
+	}
+	if token != "" {
+		authReq.Header.Set("Authorization", token)
+	}
+	return authReq, token, nil
+}
+
+// currentToken returns a valid auth record token, refreshing or signing in as needed. The lock
+// is not held during the sign-in; concurrent callers wait for the same attempt instead, or give
+// up when ctx is done.
+func (t *authTransport) currentToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	if !t.config.usesRecordAuth() {
+		t.mu.Unlock()
+		return "", nil
+	}
+	if t.token != "" && time.Until(t.expires) > tokenRefreshMargin {
+		token := t.token
+		t.mu.Unlock()
+		return token, nil
+	}
+
+	attempt := t.login
+	if attempt != nil {
+		t.mu.Unlock()
+		select {
+		case <-attempt.done:
+			return attempt.token, attempt.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	attempt = &authAttempt{done: make(chan struct{})}
+	t.login = attempt
+	config, token, expires := t.config, t.token, t.expires
+	t.mu.Unlock()
+
+	attempt.token, attempt.err = t.signIn(config, token, expires)
+
+	t.mu.Lock()
+	if attempt.err == nil {
+		t.token = attempt.token
+		t.expires = tokenExpiry(attempt.token)
+	}
+	t.login = nil
+	t.mu.Unlock()
+	close(attempt.done)
+
+	return attempt.token, attempt.err
+}
+
+// signIn refreshes a token that has not expired yet, falling back to signing in with the password
+func (t *authTransport) signIn(config AuthConfig, token string, expires time.Time) (string, error) {
+	if token != "" && time.Now().Before(expires) {
+		refreshed, err := t.authenticate(config, "auth-refresh", token, nil)
+		if err == nil {
+			return refreshed, nil
+		}
+		log.Printf("⚠️  PocketBase token refresh failed, signing in again: %v", err)
+	}
+
+	return t.authenticate(config, "auth-with-password", "", map[string]string{
+		"identity": config.Identity,
+		"password": config.Password,
+	})
+}
+
+// authenticate calls an auth endpoint of the configured collection and returns the new token
+func (t *authTransport) authenticate(config AuthConfig, action, token string, payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	if payload == nil {
+		body = []byte("{}")
+	}
+
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/api/collections/%s/%s", t.baseURL, config.Collection, action), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	// Auth requests go straight to the base transport so they are not authorized recursively
+	client := &http.Client{Transport: t.base, Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s for %s/%s failed with status: %d", action, config.Collection, config.Identity, resp.StatusCode)
+	}
+
+	var authResponse struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&authResponse); err != nil {
+		return "", err
+	}
+	if authResponse.Token == "" {
+		return "", fmt.Errorf("%s returned no token", action)
+	}
+	return authResponse.Token, nil
+}
+
+// invalidate drops a token that PocketBase rejected, unless it was already replaced
+func (t *authTransport) invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = ""
+		t.expires = time.Time{}
+	}
+}
+
+func (t *authTransport) setAgentToken(agentID, agentToken string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.config.AgentID = agentID
+	t.config.AgentToken = agentToken
+}
+
+// isAuthenticated reports whether the agent currently holds usable credentials. Without any
+// credentials configured the collections are used in public access mode. With record auth it
+// signs in when there is no valid token, so keep it off request paths.
+func (t *authTransport) isAuthenticated() bool {
+	t.mu.Lock()
+	recordAuth := t.config.usesRecordAuth()
+	t.mu.Unlock()
+
+	if !recordAuth {
+		return true // Agent token mode is verified by the API rules on each request
+	}
+	token, err := t.currentToken(context.Background())
+	return err == nil && token != ""
+}
+
+// tokenExpiry reads the exp claim of a PocketBase JWT. The signature is not checked; the
+// value is only used to decide when to refresh. Unknown expiry falls back to one hour.
+func tokenExpiry(token string) time.Time {
+	fallback := time.Now().Add(time.Hour)
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fallback
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fallback
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return fallback
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,132 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testToken builds an unsigned JWT with the given payload
+func testToken(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encode([]byte(payload)) + ".signature"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name     string
+		token    string
+		want     time.Time
+		fallback bool
+	}{
+		{name: "exp claim", token: testToken(fmt.Sprintf(`{"id":"abc","exp":%d}`, exp.Unix())), want: exp},
+		{name: "expired", token: testToken(`{"exp":1000}`), want: time.Unix(1000, 0)},
+		{name: "no exp claim", token: testToken(`{"id":"abc"}`), fallback: true},
+		{name: "invalid payload json", token: testToken(`not json`), fallback: true},
+		{name: "invalid base64", token: "header.!!!.signature", fallback: true},
+		{name: "not a jwt", token: "opaque-token", fallback: true},
+		{name: "empty", token: "", fallback: true},
+	}
+
+	for _, tt := range tests {
+		got := tokenExpiry(tt.token)
+		if tt.fallback {
+			if until := time.Until(got); until < 59*time.Minute || until > time.Hour {
+				t.Errorf("%s: expiry in %v, want the one hour fallback", tt.name, until)
+			}
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: expiry %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConcurrentRequestsShareOneSignIn(t *testing.T) {
+	var logins int32
+	release := make(chan struct{})
+	token := testToken(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix()))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/collections/agents/auth-with-password" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&logins, 1)
+		<-release
+		fmt.Fprintf(w, `{"token":%q}`, token)
+	}))
+	defer server.Close()
+
+	auth := newAuthTransport(server.URL, AuthConfig{Collection: "agents", Identity: "agent", Password: "secret"})
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = auth.currentToken(context.Background())
+		}(i)
+	}
+
+	// The lock is free while the sign-in is in flight
+	time.Sleep(50 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		auth.setAgentToken("agent-1", "agent-token")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("setAgentToken blocked behind the sign-in")
+	}
+
+	// A waiter gives up when its request is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := auth.currentToken(ctx); err != context.Canceled {
+		t.Errorf("canceled wait returned %v, want context.Canceled", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&logins); n != 1 {
+		t.Errorf("%d sign-ins, want 1 shared by all requests", n)
+	}
+	for i, got := range tokens {
+		if got != token {
+			t.Errorf("request %d got token %q", i, got)
+		}
+	}
+
+	// The stored token is reused without signing in again
+	if got, err := auth.currentToken(context.Background()); err != nil || got != token || atomic.LoadInt32(&logins) != 1 {
+		t.Errorf("cached token %q, error %v, %d sign-ins", got, err, atomic.LoadInt32(&logins))
+	}
+}
+
+func TestSignInFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid credentials", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	auth := newAuthTransport(server.URL, AuthConfig{Collection: "agents", Identity: "agent", Password: "wrong"})
+	if token, err := auth.currentToken(context.Background()); err == nil || token != "" {
+		t.Errorf("token %q, error %v, want the sign-in error", token, err)
+	}
+	if auth.isAuthenticated() {
+		t.Error("authenticated after a failed sign-in")
+	}
+}
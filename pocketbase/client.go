@@ -2,14 +2,20 @@
 package pocketbase
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
+
+	"service-operation/spool"
 )
 
 type PocketBaseClient struct {
 	baseURL    string
 	httpClient *http.Client
+	spool      *spool.Spool
+	auth       *authTransport
 }
 
 func NewPocketBaseClient(baseURL string) (*PocketBaseClient, error) {
@@ -28,6 +34,34 @@ func NewPocketBaseClient(baseURL string) (*PocketBaseClient, error) {
 	return client, nil
 }
 
+// NewPocketBaseClientWithAuth creates a client that authenticates every request with the given credentials
+func NewPocketBaseClientWithAuth(baseURL string, auth AuthConfig) (*PocketBaseClient, error) {
+	client, err := NewPocketBaseClient(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client.auth = newAuthTransport(client.baseURL, auth)
+	client.httpClient.Transport = client.auth
+
+	// Sign in up front so bad credentials are reported at startup
+	if auth.usesRecordAuth() {
+		if _, err := client.auth.currentToken(context.Background()); err != nil {
+			log.Printf("Warning: PocketBase authentication as %s/%s failed: %v", auth.Collection, auth.Identity, err)
+		}
+	}
+
+	return client, nil
+}
+
+// SetAgentToken updates the agent credentials sent to PocketBase, e.g. once the token
+// stored in regional_service is known
+func (c *PocketBaseClient) SetAgentToken(agentID, token string) {
+	if c.auth != nil {
+		c.auth.setAgentToken(agentID, token)
+	}
+}
+
 func (c *PocketBaseClient) GetBaseURL() string {
 	return c.baseURL
 }
@@ -47,6 +81,9 @@ func (c *PocketBaseClient) TestConnection() error {
 }
 
 func (c *PocketBaseClient) IsAuthenticated() bool {
-	// Since we're using public access mode, always return true
-	return true
+	// Without credentials the collections are used in public access mode
+	if c.auth == nil {
+		return true
+	}
+	return c.auth.isAuthenticated()
 }
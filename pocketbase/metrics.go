@@ -20,3 +20,7 @@ func (c *PocketBaseClient) SaveDNSData(dnsData DNSDataRecord) error {
 func (c *PocketBaseClient) SaveTCPData(tcpData TCPDataRecord) error {
 	return c.createRecord("tcp_data", tcpData)
 }
+
+func (c *PocketBaseClient) SaveTLSData(tlsData TLSDataRecord) error {
+	return c.createRecord("tls_data", tlsData)
+}
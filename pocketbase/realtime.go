@@ -0,0 +1,158 @@
+package pocketbase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ServiceEvent is a realtime change to a record in the services collection
+type ServiceEvent struct {
+	Action string  `json:"action"` // create, update or delete
+	Record Service `json:"record"`
+}
+
+// sseEvent is a single server-sent event
+type sseEvent struct {
+	id   string
+	name string
+	data string
+}
+
+// SubscribeServices subscribes to the PocketBase realtime API for the services collection and
+// calls onEvent for every change. onConnect is called once the subscription is active. It blocks
+// until the stream ends, fails or stop is closed.
+func (c *PocketBaseClient) SubscribeServices(stop <-chan bool, onConnect func(), onEvent func(ServiceEvent)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/realtime", c.baseURL), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so it must not share the client timeout
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("realtime connection failed with status: %d", resp.StatusCode)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		event, err := readSSEEvent(reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // Stopped
+			}
+			return fmt.Errorf("realtime stream closed: %v", err)
+		}
+
+		switch event.name {
+		case "PB_CONNECT":
+			var connect struct {
+				ClientID string `json:"clientId"`
+			}
+			if err := json.Unmarshal([]byte(event.data), &connect); err != nil || connect.ClientID == "" {
+				connect.ClientID = event.id
+			}
+			if err := c.setRealtimeSubscriptions(ctx, connect.ClientID); err != nil {
+				return err
+			}
+			if onConnect != nil {
+				onConnect()
+			}
+
+		case "services", "services/*":
+			var serviceEvent ServiceEvent
+			if err := json.Unmarshal([]byte(event.data), &serviceEvent); err != nil {
+				continue
+			}
+			onEvent(serviceEvent)
+		}
+	}
+}
+
+// setRealtimeSubscriptions registers the services topic for a realtime client. Both the
+// pre-0.23 ("services") and current ("services/*") topic forms are sent.
+func (c *PocketBaseClient) setRealtimeSubscriptions(ctx context.Context, clientID string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"clientId":      clientID,
+		"subscriptions": []string{"services", "services/*"},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/realtime", c.baseURL), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("realtime subscription failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// readSSEEvent reads lines until a complete event has been received
+func readSSEEvent(reader *bufio.Reader) (*sseEvent, error) {
+	event := &sseEvent{}
+	var data []string
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if event.name == "" && len(data) == 0 {
+				continue
+			}
+			event.data = strings.Join(data, "\n")
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment / keepalive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
@@ -4,16 +4,70 @@ package pocketbase
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+
+	"service-operation/exporter"
 )
 
+// spooledCollections are the result collections buffered on disk when a write fails
+var spooledCollections = map[string]bool{
+	"services_metrics": true,
+	"ping_data":        true,
+	"dns_data":         true,
+	"tcp_data":         true,
+	"uptime_data":      true,
+	"tls_data":         true,
+}
+
+// recordStatusError is returned when PocketBase answers a write with an unexpected status
+type recordStatusError struct {
+	collection string
+	statusCode int
+}
+
+func (e *recordStatusError) Error() string {
+	return fmt.Sprintf("failed to create record in %s, status: %d", e.collection, e.statusCode)
+}
+
+// isRetryableWriteError reports whether a failed write may succeed later. Validation
+// errors (other 4xx responses) will never succeed and are not worth buffering.
+func isRetryableWriteError(err error) bool {
+	var statusErr *recordStatusError
+	if !errors.As(err, &statusErr) {
+		return true // Network errors
+	}
+
+	switch statusErr.statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return statusErr.statusCode >= 500
+}
+
 func (c *PocketBaseClient) createRecord(collection string, data interface{}) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
+	err = c.postRecord(collection, jsonData)
+	if err != nil {
+		exporter.PocketBaseWriteFailures.Inc()
+	}
+	if err == nil || c.spool == nil || !spooledCollections[collection] || !isRetryableWriteError(err) {
+		return err
+	}
+
+	// Buffer the write on disk so it can be replayed once the backend is reachable again
+	if spoolErr := c.spool.Append(collection, json.RawMessage(jsonData)); spoolErr != nil {
+		return fmt.Errorf("%v (spooling failed: %v)", err, spoolErr)
+	}
+	return nil
+}
+
+func (c *PocketBaseClient) postRecord(collection string, jsonData []byte) error {
 	resp, err := c.httpClient.Post(
 		fmt.Sprintf("%s/api/collections/%s/records", c.baseURL, collection),
 		"application/json",
@@ -25,7 +79,7 @@ func (c *PocketBaseClient) createRecord(collection string, data interface{}) err
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create record in %s, status: %d", collection, resp.StatusCode)
+		return &recordStatusError{collection: collection, statusCode: resp.StatusCode}
 	}
 
 	return nil
@@ -48,11 +102,13 @@ func (c *PocketBaseClient) updateRecord(collection, recordID string, data interf
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		exporter.PocketBaseWriteFailures.Inc()
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		exporter.PocketBaseWriteFailures.Inc()
 		return fmt.Errorf("failed to update record %s in %s, status: %d", recordID, collection, resp.StatusCode)
 	}
 
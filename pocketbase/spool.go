@@ -0,0 +1,74 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"service-operation/spool"
+)
+
+// SetSpool enables buffering of failed result writes in the given spool
+func (c *PocketBaseClient) SetSpool(s *spool.Spool) {
+	c.spool = s
+}
+
+// ReplaySpool sends buffered result writes in the order they were queued.
+// It is safe to call repeatedly; only one replay runs at a time.
+func (c *PocketBaseClient) ReplaySpool() {
+	c.replaySpool(context.Background())
+}
+
+// replaySpool replays buffered writes until ctx is done
+func (c *PocketBaseClient) replaySpool(ctx context.Context) {
+	if c.spool == nil || c.spool.Pending() == 0 {
+		return
+	}
+
+	replayed, err := c.spool.Replay(ctx, func(entry spool.Entry) error {
+		err := c.postRecord(entry.Collection, entry.Data)
+		if err != nil && !isRetryableWriteError(err) {
+			return fmt.Errorf("%w: %v", spool.ErrRejected, err)
+		}
+		return err
+	})
+
+	if replayed > 0 {
+		log.Printf("📤 Replayed %d spooled records to PocketBase", replayed)
+	}
+	switch {
+	case err == nil:
+	case ctx.Err() != nil || errors.Is(err, spool.ErrClosed):
+		log.Printf("Spool replay interrupted, %d bytes kept for the next replay", c.spool.Pending())
+	default:
+		log.Printf("Spool replay paused, backend write failed: %v", err)
+	}
+}
+
+// FlushSpool replays buffered writes once more if the backend is reachable and closes the
+// active segment. It gives up on the replay when ctx expires; closing the spool then stops
+// any replay still running after its current entry.
+func (c *PocketBaseClient) FlushSpool(ctx context.Context) {
+	if c.spool == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if c.TestConnection() == nil {
+			c.replaySpool(ctx)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("⚠️  Spool replay interrupted by shutdown, %d bytes kept for the next start", c.spool.Pending())
+	}
+
+	if err := c.spool.Close(); err != nil {
+		log.Printf("Failed to close spool: %v", err)
+	}
+}
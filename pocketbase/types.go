@@ -16,6 +16,50 @@ type Service struct {
 	Host              string `json:"host"`
 	Port              int    `json:"port"`
 	Domain            string `json:"domain"`         // Added missing Domain field
+
+	// Retry and status confirmation
+	MaxRetries        int `json:"max_retries"`        // Retries of a failed probe within one check, -1 for none
+	RetryInterval     int `json:"retry_interval"`     // Seconds between retries
+	FailureThreshold  int `json:"failure_threshold"`  // Consecutive failed checks before down
+	RecoveryThreshold int `json:"recovery_threshold"` // Consecutive successful checks before up
+
+	// TLS certificate thresholds
+	TLSWarningDays int `json:"tls_warning_days"` // Days left before a TLS check reports a warning
+	TLSDownDays    int `json:"tls_down_days"`    // Days left before a TLS check reports down
+
+	// HTTP response assertions
+	StatusCodes      string          `json:"status_codes"`      // Accepted status codes, e.g. "200-299,301"
+	Keyword          string          `json:"keyword"`           // Required body keyword
+	ForbiddenKeyword string          `json:"forbidden_keyword"` // Keyword that must not appear in the body
+	KeywordRegex     string          `json:"keyword_regex"`     // Regex the body must match
+	ForbiddenRegex   string          `json:"forbidden_regex"`   // Regex the body must not match
+	JSONAssertions   []JSONAssertion `json:"json_assertions"`   // JSON path equality checks
+
+	// HTTP request customization
+	Method            string            `json:"method"`
+	Headers           map[string]string `json:"headers"`
+	Body              string            `json:"body"`
+	ContentType       string            `json:"content_type"`
+	BasicAuthUser     string            `json:"basic_auth_user"`
+	BasicAuthPassword string            `json:"basic_auth_password"`
+	BearerToken       string            `json:"bearer_token"`
+	NoFollowRedirects bool              `json:"no_follow_redirects"` // PocketBase returns false for unset bools, so following is the zero value
+	MaxRedirects      int               `json:"max_redirects"`
+	IgnoreTLSError    bool              `json:"ignore_tls_error"`
+
+	// DNS query and expected answers
+	DNSQueryType     string   `json:"dns_query_type"`     // Record type to query, defaults to A
+	DNSServer        string   `json:"dns_server"`         // host, host:port or DoH URL; empty uses the system nameserver
+	DNSProtocol      string   `json:"dns_protocol"`       // udp, tcp, dot or doh
+	DNSExpected      []string `json:"dns_expected"`       // Expected answers; a mismatch marks the service down
+	DNSExpectedMatch string   `json:"dns_expected_match"` // exact (default) or contains
+	DNSSEC           bool     `json:"dnssec"`             // Validate answers with DNSSEC; bogus marks the service down
+}
+
+// JSONAssertion is a JSON path equality check stored as a json field on a service
+type JSONAssertion struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
 }
 
 type ServicesResponse struct {
@@ -62,7 +106,12 @@ type MetricsData struct {
 type MetricsRecord struct {
 	ServiceName       string `json:"service_name"`
 	Host              string `json:"host"`
-	Uptime            float64 `json:"uptime"`
+	Uptime            float64 `json:"uptime"`            // Percentage over the last 24 hours
+	Uptime7d          float64 `json:"uptime_7d"`         // Percentage over the last 7 days
+	Uptime30d         float64 `json:"uptime_30d"`        // Percentage over the last 30 days
+	ResponseTimeP50   int64   `json:"response_time_p50"` // Milliseconds, last 24 hours
+	ResponseTimeP95   int64   `json:"response_time_p95"`
+	ResponseTimeP99   int64   `json:"response_time_p99"`
 	ResponseTime      int64   `json:"response_time"`
 	LastChecked       string  `json:"last_checked"`
 	Port              int     `json:"port,omitempty"`
@@ -102,49 +151,84 @@ type PingDataRecord struct {
 }
 
 type UptimeDataRecord struct {
-	ServiceID     string    `json:"service_id"`
-	Timestamp     time.Time `json:"timestamp"`
-	ResponseTime  int64     `json:"response_time"`
-	Status        string    `json:"status"`
-	Packets       string    `json:"packets"`
-	Latency       string    `json:"latency"`
-	StatusCodes   string    `json:"status_codes"`
-	Keyword       string    `json:"keyword"`
-	ErrorMessage  string    `json:"error_message"`
-	Details       string    `json:"details"`
-	Region        string    `json:"region,omitempty"`
-	RegionID      string    `json:"region_id,omitempty"`
-	RegionName    string    `json:"region_name,omitempty"`
-	AgentID       string    `json:"agent_id,omitempty"`
+	ServiceID       string    `json:"service_id"`
+	Timestamp       time.Time `json:"timestamp"`
+	ResponseTime    int64     `json:"response_time"`
+	Status          string    `json:"status"`
+	Packets         string    `json:"packets"`
+	Latency         string    `json:"latency"`
+	StatusCodes     string    `json:"status_codes"`
+	Keyword         string    `json:"keyword"`
+	ErrorMessage    string    `json:"error_message"`
+	Details         string    `json:"details"`
+	DNSLookup       float64   `json:"dns_lookup"`       // Milliseconds
+	TCPConnect      float64   `json:"tcp_connect"`      // Milliseconds
+	TLSHandshake    float64   `json:"tls_handshake"`    // Milliseconds
+	TimeToFirstByte float64   `json:"ttfb"`             // Milliseconds
+	ContentTransfer float64   `json:"content_transfer"` // Milliseconds
+	Region          string    `json:"region,omitempty"`
+	RegionID        string    `json:"region_id,omitempty"`
+	RegionName      string    `json:"region_name,omitempty"`
+	AgentID         string    `json:"agent_id,omitempty"`
 }
 
 type DNSDataRecord struct {
+	ServiceID      string    `json:"service_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	ResponseTime   int64     `json:"response_time"`
+	Status         string    `json:"status"`
+	QueryType      string    `json:"query_type"`
+	ResolveIP      string    `json:"resolve_ip"`
+	MsgSize        string    `json:"msg_size"`
+	Question       string    `json:"question"`
+	Answer         string    `json:"answer"`
+	Authority      string    `json:"authority"`
+	Additional     string    `json:"additional"`
+	Rcode          string    `json:"rcode"`
+	Authoritative  bool      `json:"authoritative"`
+	Server         string    `json:"server"`
+	AnswerChanged  bool      `json:"answer_changed"`
+	PreviousAnswer string    `json:"previous_answer,omitempty"`
+	DNSSEC         string    `json:"dnssec,omitempty"`
+	DNSSECReason   string    `json:"dnssec_reason,omitempty"`
+	ErrorMessage   string    `json:"error_message,omitempty"`
+	Details        string    `json:"details,omitempty"`
+	RegionName     string    `json:"region_name,omitempty"`
+	AgentID        string    `json:"agent_id,omitempty"`
+}
+
+type TCPDataRecord struct {
 	ServiceID    string    `json:"service_id"`
 	Timestamp    time.Time `json:"timestamp"`
 	ResponseTime int64     `json:"response_time"`
 	Status       string    `json:"status"`
-	QueryType    string    `json:"query_type"`
-	ResolveIP    string    `json:"resolve_ip"`
-	MsgSize      string    `json:"msg_size"`
-	Question     string    `json:"question"`
-	Answer       string    `json:"answer"`
-	Authority    string    `json:"authority"`
+	Connection   string    `json:"connection"`
+	Latency      string    `json:"latency"`
+	Port         string    `json:"port"`
 	ErrorMessage string    `json:"error_message,omitempty"`
 	Details      string    `json:"details,omitempty"`
 	RegionName   string    `json:"region_name,omitempty"`
 	AgentID      string    `json:"agent_id,omitempty"`
 }
-
-type TCPDataRecord struct {
+type TLSDataRecord struct {
 	ServiceID    string    `json:"service_id"`
 	Timestamp    time.Time `json:"timestamp"`
 	ResponseTime int64     `json:"response_time"`
 	Status       string    `json:"status"`
-	Connection   string    `json:"connection"`
-	Latency      string    `json:"latency"`
+	Host         string    `json:"host"`
 	Port         string    `json:"port"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SANs         string    `json:"sans"`
+	NotBefore    string    `json:"not_before"`
+	NotAfter     string    `json:"not_after"`
+	DaysLeft     int       `json:"days_left"`
+	TLSVersion   string    `json:"tls_version"`
+	CipherSuite  string    `json:"cipher_suite"`
+	ChainValid   bool      `json:"chain_valid"`
+	ChainError   string    `json:"chain_error,omitempty"`
 	ErrorMessage string    `json:"error_message,omitempty"`
 	Details      string    `json:"details,omitempty"`
 	RegionName   string    `json:"region_name,omitempty"`
 	AgentID      string    `json:"agent_id,omitempty"`
-}
\ No newline at end of file
+}
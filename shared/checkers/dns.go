@@ -0,0 +1,121 @@
+package checkers
+
+import (
+	"context"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+type dnsChecker struct{}
+
+func init() {
+	Register(&dnsChecker{})
+}
+
+func (c *dnsChecker) Type() types.OperationType {
+	return types.OperationDNS
+}
+
+func (c *dnsChecker) ServiceTypes() []string {
+	return nil
+}
+
+func (c *dnsChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = types.OperationDNS
+	if req.Query == "" {
+		req.Query = "A"
+	}
+	if err := operations.ValidateDNSQueryType(req.Query); err != nil {
+		return err
+	}
+	return operations.ValidateDNSOptions(&req.DNSOptions)
+}
+
+func (c *dnsChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	host := service.Host
+	if host == "" {
+		host = service.Domain
+	}
+
+	query := service.DNSQueryType
+	if query == "" {
+		query = "A"
+	}
+
+	return types.OperationRequest{
+		Type:  types.OperationDNS,
+		Host:  host,
+		Query: query,
+		DNSOptions: types.DNSOptions{
+			DNSServer:        service.DNSServer,
+			DNSProtocol:      service.DNSProtocol,
+			DNSExpected:      service.DNSExpected,
+			DNSExpectedMatch: service.DNSExpectedMatch,
+			DNSSEC:           service.DNSSEC,
+		},
+		ServiceID: service.ID,
+	}
+}
+
+func (c *dnsChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	dnsOp := operations.NewDNSOperationWithOptions(timeout, &req.DNSOptions)
+	return dnsOp.Execute(ctx, req.Host, req.Query)
+}
+
+func (c *dnsChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SaveDNSDataToPocketBase(result, serviceID)
+}
+
+func (c *dnsChecker) Details(result *types.OperationResult) map[string]interface{} {
+	details := map[string]interface{}{
+		"dns_records": result.DNSRecords,
+		"dns_type":    result.DNSType,
+		"dns_server":  result.DNSServer,
+		"dns_rcode":   result.DNSRcode,
+	}
+	if result.FailedAssertion != "" {
+		details["failed_assertion"] = result.FailedAssertion
+	}
+	if result.DNSSECStatus != "" {
+		details["dnssec_status"] = result.DNSSECStatus
+		details["dnssec_reason"] = result.DNSSECReason
+	}
+	return details
+}
+
+func (c *dnsChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	families := []exporter.Family{
+		exporter.GaugeFamily("probe_dns_lookup_time_seconds", "Returns the time taken for probe dns lookup in seconds.", result.ResponseTime.Seconds()),
+		exporter.GaugeFamily("probe_dns_answer_rrs", "Returns number of entries in the answer resource record list.", float64(len(result.DNSAnswer))),
+		exporter.GaugeFamily("probe_dns_authority_rrs", "Returns number of entries in the authority resource record list.", float64(len(result.DNSAuthority))),
+		exporter.GaugeFamily("probe_dns_additional_rrs", "Returns number of entries in the additional resource record list.", float64(len(result.DNSAdditional))),
+		exporter.GaugeFamily("probe_dns_authoritative", "Whether the response had the authoritative answer flag set.", exporter.BoolValue(result.DNSAuthoritative)),
+		exporter.GaugeFamily("probe_failed_due_to_assertion", "Indicates if the probe failed due to an unexpected answer.", exporter.BoolValue(result.FailedAssertion != "")),
+	}
+	if result.DNSSECStatus != "" {
+		families = append(families, exporter.Family{
+			Name: "probe_dns_dnssec_status", Help: "DNSSEC validation status of the answer.", Type: "gauge",
+			Samples: []exporter.Sample{{Labels: []exporter.Label{{Name: "status", Value: result.DNSSECStatus}}, Value: 1}},
+		})
+	}
+	return families
+}
+
+func (c *dnsChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	fields := map[string]interface{}{
+		"dns_records":        len(result.DNSRecords),
+		"dns_answer_changed": result.DNSAnswerChanged,
+	}
+	if result.DNSRcode != "" {
+		fields["dns_rcode"] = result.DNSRcode
+	}
+	if result.DNSSECStatus != "" {
+		fields["dnssec_status"] = result.DNSSECStatus
+	}
+	return fields
+}
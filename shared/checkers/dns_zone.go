@@ -0,0 +1,91 @@
+package checkers
+
+import (
+	"context"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+// dnsZoneChecker compares the SOA serial of a zone across its authoritative nameservers
+type dnsZoneChecker struct{}
+
+func init() {
+	Register(&dnsZoneChecker{})
+}
+
+func (c *dnsZoneChecker) Type() types.OperationType {
+	return operations.OperationDNSZone
+}
+
+func (c *dnsZoneChecker) ServiceTypes() []string {
+	return nil
+}
+
+func (c *dnsZoneChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = operations.OperationDNSZone
+	return operations.ValidateDNSOptions(&req.DNSOptions)
+}
+
+func (c *dnsZoneChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	zone := service.Domain
+	if zone == "" {
+		zone = service.Host
+	}
+
+	return types.OperationRequest{
+		Type: operations.OperationDNSZone,
+		Host: zone,
+		DNSOptions: types.DNSOptions{
+			DNSServer:   service.DNSServer,
+			DNSProtocol: service.DNSProtocol,
+		},
+		ServiceID: service.ID,
+	}
+}
+
+func (c *dnsZoneChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	zoneOp := operations.NewDNSZoneOperationWithOptions(timeout, &req.DNSOptions)
+	return zoneOp.Execute(ctx, req.Host)
+}
+
+func (c *dnsZoneChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SaveDNSZoneDataToPocketBase(result, serviceID)
+}
+
+func (c *dnsZoneChecker) Details(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{
+		"dns_records":      result.DNSRecords,
+		"dns_server":       result.DNSServer,
+		"dns_zone_servers": result.DNSZoneServers,
+	}
+}
+
+func (c *dnsZoneChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	serials := exporter.Family{Name: "probe_dns_serial", Help: "Returns the SOA serial of the zone by nameserver.", Type: "gauge"}
+	consistent := len(result.DNSZoneServers) > 0
+	for _, server := range result.DNSZoneServers {
+		if server.Error != "" {
+			consistent = false
+			continue
+		}
+		labels := []exporter.Label{{Name: "nameserver", Value: server.Nameserver}, {Name: "address", Value: server.Address}}
+		serials.Samples = append(serials.Samples, exporter.Sample{Labels: labels, Value: float64(server.Serial)})
+		if server.Serial != result.DNSZoneServers[0].Serial {
+			consistent = false
+		}
+	}
+
+	return []exporter.Family{serials,
+		exporter.GaugeFamily("probe_dns_zone_nameservers", "Returns number of authoritative nameserver addresses queried.", float64(len(result.DNSZoneServers))),
+		exporter.GaugeFamily("probe_dns_zone_consistent", "Whether every nameserver answered with the same SOA serial.", exporter.BoolValue(consistent)),
+	}
+}
+
+func (c *dnsZoneChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{"dns_zone_nameservers": len(result.DNSZoneServers)}
+}
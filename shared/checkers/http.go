@@ -0,0 +1,154 @@
+package checkers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+type httpChecker struct{}
+
+func init() {
+	Register(&httpChecker{})
+}
+
+func (c *httpChecker) Type() types.OperationType {
+	return types.OperationHTTP
+}
+
+func (c *httpChecker) ServiceTypes() []string {
+	return []string{"https"}
+}
+
+func (c *httpChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = types.OperationHTTP
+	if req.URL == "" {
+		req.URL = req.Host
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+	req.Method = strings.ToUpper(req.Method)
+	if req.MaxRedirects < 0 {
+		return fmt.Errorf("max_redirects cannot be negative")
+	}
+	return operations.ValidateHTTPAssertions(req.Assertions)
+}
+
+func (c *httpChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	url := service.URL
+	if url == "" {
+		url = service.Host
+	}
+	method := strings.ToUpper(service.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	return types.OperationRequest{
+		Type:   types.OperationHTTP,
+		Host:   service.Host,
+		URL:    url,
+		Method: method,
+		HTTPOptions: types.HTTPOptions{
+			Headers:            service.Headers,
+			Body:               service.Body,
+			ContentType:        service.ContentType,
+			BasicAuthUser:      service.BasicAuthUser,
+			BasicAuthPassword:  service.BasicAuthPassword,
+			BearerToken:        service.BearerToken,
+			NoFollowRedirects:  service.NoFollowRedirects,
+			MaxRedirects:       service.MaxRedirects,
+			InsecureSkipVerify: service.IgnoreTLSError,
+		},
+		Assertions: assertionsFromService(service),
+		ServiceID:  service.ID,
+	}
+}
+
+func (c *httpChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	httpOp := operations.NewHTTPOperationWithOptions(timeout, &req.HTTPOptions, req.Assertions)
+	return httpOp.Execute(ctx, req.URL, req.Method)
+}
+
+func (c *httpChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SaveUptimeDataToPocketBase(result, serviceID)
+}
+
+// assertionsFromService maps the assertion fields of a service record, returning nil when none are set
+func assertionsFromService(service pocketbase.Service) *types.HTTPAssertions {
+	assertions := &types.HTTPAssertions{
+		StatusCodes: service.StatusCodes,
+	}
+
+	if service.Keyword != "" {
+		assertions.Keywords = []string{service.Keyword}
+	}
+	if service.ForbiddenKeyword != "" {
+		assertions.ForbiddenKeywords = []string{service.ForbiddenKeyword}
+	}
+	if service.KeywordRegex != "" {
+		assertions.Regexes = []string{service.KeywordRegex}
+	}
+	if service.ForbiddenRegex != "" {
+		assertions.ForbiddenRegexes = []string{service.ForbiddenRegex}
+	}
+	for _, ja := range service.JSONAssertions {
+		assertions.JSONPaths = append(assertions.JSONPaths, types.JSONPathAssertion{
+			Path:     ja.Path,
+			Expected: ja.Expected,
+		})
+	}
+
+	if assertions.StatusCodes == "" && len(assertions.Keywords) == 0 && len(assertions.ForbiddenKeywords) == 0 &&
+		len(assertions.Regexes) == 0 && len(assertions.ForbiddenRegexes) == 0 && len(assertions.JSONPaths) == 0 {
+		return nil
+	}
+	return assertions
+}
+
+func (c *httpChecker) Details(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{
+		"status_code":    result.HTTPStatusCode,
+		"method":         result.HTTPMethod,
+		"content_length": result.ContentLength,
+	}
+}
+
+func (c *httpChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	phases := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"resolve", result.HTTPDNSLookup},
+		{"connect", result.HTTPTCPConnect},
+		{"tls", result.HTTPTLSHandshake},
+		{"processing", result.HTTPTimeToFirstByte},
+		{"transfer", result.HTTPContentTransfer},
+	}
+	durations := exporter.Family{Name: "probe_http_duration_seconds", Help: "Duration of the HTTP request by phase.", Type: "gauge"}
+	for _, phase := range phases {
+		durations.Samples = append(durations.Samples, exporter.Sample{Labels: []exporter.Label{{Name: "phase", Value: phase.name}}, Value: phase.duration.Seconds()})
+	}
+
+	return []exporter.Family{durations,
+		exporter.GaugeFamily("probe_http_status_code", "Response HTTP status code.", float64(result.HTTPStatusCode)),
+		exporter.GaugeFamily("probe_http_content_length", "Length of the HTTP content response.", float64(result.ContentLength)),
+		exporter.GaugeFamily("probe_http_ssl", "Indicates if SSL was used for the final request.", exporter.BoolValue(result.HTTPTLSHandshake > 0)),
+		exporter.GaugeFamily("probe_failed_due_to_assertion", "Indicates if the probe failed due to a response assertion.", exporter.BoolValue(result.FailedAssertion != "")),
+	}
+}
+
+func (c *httpChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	if result.HTTPStatusCode == 0 {
+		return nil
+	}
+	return map[string]interface{}{"http_status_code": result.HTTPStatusCode}
+}
@@ -0,0 +1,81 @@
+package checkers
+
+import (
+	"context"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+type pingChecker struct{}
+
+func init() {
+	Register(&pingChecker{})
+}
+
+func (c *pingChecker) Type() types.OperationType {
+	return types.OperationPing
+}
+
+func (c *pingChecker) ServiceTypes() []string {
+	return []string{"icmp"}
+}
+
+func (c *pingChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = types.OperationPing
+	return nil
+}
+
+func (c *pingChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	host := service.Host
+	if host == "" {
+		host = service.URL
+	}
+
+	return types.OperationRequest{
+		Type:      types.OperationPing,
+		Host:      host,
+		Count:     1, // Single ping for monitoring
+		ServiceID: service.ID,
+	}
+}
+
+func (c *pingChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	return operations.NewPingOperation(timeout).Execute(ctx, req.Host, req.Count)
+}
+
+func (c *pingChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SavePingDataToPocketBase(result, serviceID)
+}
+
+func (c *pingChecker) Details(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{
+		"packets_sent": result.PacketsSent,
+		"packets_recv": result.PacketsRecv,
+		"packet_loss":  result.PacketLoss,
+		"avg_rtt":      result.AvgRTT,
+	}
+}
+
+func (c *pingChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	families := []exporter.Family{
+		exporter.GaugeFamily("probe_icmp_packets_sent", "Number of ICMP echo requests sent.", float64(result.PacketsSent)),
+		exporter.GaugeFamily("probe_icmp_packets_received", "Number of ICMP echo replies received.", float64(result.PacketsRecv)),
+		exporter.GaugeFamily("probe_icmp_packet_loss_ratio", "Fraction of ICMP echo requests without a reply.", result.PacketLoss/100),
+	}
+	if result.PacketsRecv > 0 {
+		families = append(families, exporter.Family{
+			Name: "probe_icmp_duration_seconds", Help: "Duration of the ICMP request by phase.", Type: "gauge",
+			Samples: []exporter.Sample{{Labels: []exporter.Label{{Name: "phase", Value: "rtt"}}, Value: result.AvgRTT.Seconds()}},
+		})
+	}
+	return families
+}
+
+func (c *pingChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{"packet_loss": result.PacketLoss}
+}
@@ -0,0 +1,101 @@
+package checkers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/sinks"
+	"service-operation/types"
+)
+
+// Checker describes a single check type. Each implementation owns how its
+// requests are parsed, how the check is executed and how its results are
+// persisted and exported, so adding a type never requires touching the
+// monitor loop, the handlers, the savers, the exporter or the sinks.
+type Checker interface {
+	// Type is the operation type reported in results. Types other than the
+	// built-in ones declare their own constant next to their operation.
+	Type() types.OperationType
+
+	// ServiceTypes lists the service_type values (including aliases) handled by this checker
+	ServiceTypes() []string
+
+	// ParseRequest validates an API request and fills in type specific defaults
+	ParseRequest(req *types.OperationRequest) error
+
+	// RequestFromService builds the request used by the monitor for a service record
+	RequestFromService(service pocketbase.Service) types.OperationRequest
+
+	// Execute runs the check. Canceling ctx aborts it, e.g. when the monitor is stopped.
+	Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error)
+
+	// SaveDetails stores the type specific data record for a result
+	SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string)
+
+	// Details returns the type specific fields of the /operation response details
+	Details(result *types.OperationResult) map[string]interface{}
+
+	// ProbeFamilies returns the type specific /probe metrics, with blackbox_exporter names
+	ProbeFamilies(result *types.OperationResult) []exporter.Family
+
+	// Fields returns the type specific fields written by time series sinks
+	Fields(result *types.OperationResult) map[string]interface{}
+}
+
+var (
+	mu       sync.RWMutex
+	checkers = make(map[string]Checker)
+)
+
+// Register makes a checker available to the monitor, the API, the savers, the exporter and the sinks.
+// Registering a service type twice panics, as it always indicates a programming error.
+func Register(c Checker) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := append([]string{string(c.Type())}, c.ServiceTypes()...)
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if existing, ok := checkers[key]; ok && existing != c {
+			panic(fmt.Sprintf("checkers: service type %q registered twice", name))
+		}
+		checkers[key] = c
+	}
+
+	savers.RegisterDetailSaver(c.Type(), c.SaveDetails)
+	exporter.RegisterProbeFamilies(c.Type(), c.ProbeFamilies)
+	sinks.RegisterFields(c.Type(), c.Fields)
+}
+
+// Lookup returns the checker for an operation or service type (case-insensitive)
+func Lookup(serviceType string) (Checker, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := checkers[strings.ToLower(strings.TrimSpace(serviceType))]
+	return c, ok
+}
+
+// Types returns the sorted list of registered operation types
+func Types() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	seen := make(map[types.OperationType]bool)
+	var result []string
+	for _, c := range checkers {
+		if !seen[c.Type()] {
+			seen[c.Type()] = true
+			result = append(result, string(c.Type()))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
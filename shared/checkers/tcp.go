@@ -0,0 +1,73 @@
+package checkers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+type tcpChecker struct{}
+
+func init() {
+	Register(&tcpChecker{})
+}
+
+func (c *tcpChecker) Type() types.OperationType {
+	return types.OperationTCP
+}
+
+func (c *tcpChecker) ServiceTypes() []string {
+	return nil
+}
+
+func (c *tcpChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = types.OperationTCP
+	if req.Port <= 0 {
+		return fmt.Errorf("port is required for TCP operations")
+	}
+	return nil
+}
+
+func (c *tcpChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	host := service.Host
+	if host == "" {
+		host = service.URL
+	}
+	port := service.Port
+	if port <= 0 {
+		port = 80 // Default port
+	}
+
+	return types.OperationRequest{
+		Type:      types.OperationTCP,
+		Host:      host,
+		Port:      port,
+		ServiceID: service.ID,
+	}
+}
+
+func (c *tcpChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	return operations.NewTCPOperation(timeout).Execute(ctx, req.Host, req.Port)
+}
+
+func (c *tcpChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SaveTCPDataToPocketBase(result, serviceID)
+}
+
+func (c *tcpChecker) Details(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{"tcp_connected": result.TCPConnected}
+}
+
+func (c *tcpChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	return nil
+}
+
+func (c *tcpChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	return nil
+}
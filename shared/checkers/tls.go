@@ -0,0 +1,125 @@
+package checkers
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-operation/exporter"
+	"service-operation/operations"
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+	"service-operation/types"
+)
+
+type tlsChecker struct{}
+
+func init() {
+	Register(&tlsChecker{})
+}
+
+func (c *tlsChecker) Type() types.OperationType {
+	return types.OperationTLS
+}
+
+func (c *tlsChecker) ServiceTypes() []string {
+	return []string{"ssl"}
+}
+
+func (c *tlsChecker) ParseRequest(req *types.OperationRequest) error {
+	req.Type = types.OperationTLS
+	if req.Host == "" {
+		req.Host, req.Port = splitTLSTarget(req.URL, req.Port)
+	}
+	if req.Port <= 0 {
+		req.Port = 443
+	}
+	return nil
+}
+
+func (c *tlsChecker) RequestFromService(service pocketbase.Service) types.OperationRequest {
+	host, port := service.Host, service.Port
+	if host == "" {
+		host, port = splitTLSTarget(service.URL, port)
+	}
+	if host == "" {
+		host = service.Domain
+	}
+	if port <= 0 {
+		port = 443
+	}
+
+	return types.OperationRequest{
+		Type:           types.OperationTLS,
+		Host:           host,
+		Port:           port,
+		TLSWarningDays: service.TLSWarningDays,
+		TLSDownDays:    service.TLSDownDays,
+		ServiceID:      service.ID,
+	}
+}
+
+func (c *tlsChecker) Execute(ctx context.Context, req types.OperationRequest, timeout time.Duration) (*types.OperationResult, error) {
+	tlsOp := operations.NewTLSOperationWithThresholds(timeout, req.TLSWarningDays, req.TLSDownDays, req.ServerName)
+	return tlsOp.Execute(ctx, req.Host, req.Port)
+}
+
+func (c *tlsChecker) SaveDetails(ms *savers.MetricsSaver, result *types.OperationResult, serviceID string) {
+	ms.SaveTLSDataToPocketBase(result, serviceID)
+}
+
+// splitTLSTarget extracts host and port from a URL or host:port value
+func splitTLSTarget(target string, port int) (string, int) {
+	if target == "" {
+		return "", port
+	}
+
+	if strings.Contains(target, "://") {
+		if u, err := url.Parse(target); err == nil {
+			target = u.Host
+		}
+	}
+
+	host, portStr, err := net.SplitHostPort(target)
+	if err != nil {
+		return target, port
+	}
+	if port <= 0 {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			port = p
+		}
+	}
+	return host, port
+}
+
+func (c *tlsChecker) Details(result *types.OperationResult) map[string]interface{} {
+	return map[string]interface{}{
+		"tls_days_left":   result.TLSDaysLeft, // null when no certificate was received
+		"tls_issuer":      result.TLSIssuer,
+		"tls_chain_valid": result.TLSChainValid,
+	}
+}
+
+func (c *tlsChecker) ProbeFamilies(result *types.OperationResult) []exporter.Family {
+	if result.TLSNotAfter == nil {
+		return nil
+	}
+	return []exporter.Family{
+		exporter.GaugeFamily("probe_ssl_earliest_cert_expiry", "Returns the expiry of the leaf certificate as a Unix timestamp.", float64(result.TLSNotAfter.Unix())),
+		exporter.GaugeFamily("probe_tls_chain_valid", "Whether the certificate chain validated against the system roots.", exporter.BoolValue(result.TLSChainValid)),
+		exporter.Family{
+			Name: "probe_tls_version_info", Help: "Returns the TLS version used.", Type: "gauge",
+			Samples: []exporter.Sample{{Labels: []exporter.Label{{Name: "version", Value: result.TLSVersion}}, Value: 1}},
+		},
+	}
+}
+
+func (c *tlsChecker) Fields(result *types.OperationResult) map[string]interface{} {
+	if result.TLSDaysLeft == nil {
+		return nil
+	}
+	return map[string]interface{}{"tls_days_left": *result.TLSDaysLeft}
+}
@@ -46,6 +46,66 @@ func (ms *MetricsSaver) SaveDNSDataToPocketBase(result *types.OperationResult, s
 		}
 	}
 
+	dnsData := pocketbase.DNSDataRecord{
+		ServiceID:     serviceID,
+		Timestamp:     time.Now(),
+		ResponseTime:  result.ResponseTime.Milliseconds(),
+		Status:        GetStatusString(result.Success),
+		QueryType:     result.DNSType,
+		ResolveIP:     strings.Join(result.DNSRecords, ","),
+		MsgSize:       fmt.Sprintf("%d", result.DNSMsgSize),
+		Question:      result.Host,
+		Answer:        formatDNSRecords(result.DNSAnswer),
+		Authority:     formatDNSRecords(result.DNSAuthority),
+		Additional:    formatDNSRecords(result.DNSAdditional),
+		Rcode:         result.DNSRcode,
+		Authoritative: result.DNSAuthoritative,
+		Server:        result.DNSServer,
+		AnswerChanged: result.DNSAnswerChanged,
+		DNSSEC:        result.DNSSECStatus,
+		DNSSECReason:  result.DNSSECReason,
+		ErrorMessage:  result.Error,
+		Details:       details,       // Short, clean message
+		RegionName:    ms.regionName, // Use actual regional info
+		AgentID:       ms.agentID,    // Use actual agent ID
+	}
+
+	if result.DNSAnswerChanged {
+		dnsData.PreviousAnswer = strings.Join(result.DNSPreviousRecords, ",")
+		dnsData.Details += " | 🔄 Answer changed"
+	}
+
+	if err := ms.pbClient.SaveDNSData(dnsData); err != nil {
+		println("Failed to save DNS data to PocketBase:", err.Error())
+	}
+}
+
+// formatDNSRecords renders a response section one record per line, e.g. "example.com. 300 A 93.184.216.34"
+func formatDNSRecords(records []types.DNSRecord) string {
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf("%s %d %s %s", record.Name, record.TTL, record.Type, record.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Method for monitoring service usage
+func (ms *MetricsSaver) SaveDNSDataForService(service pocketbase.Service, result *types.OperationResult) {
+	ms.SaveDNSDataToPocketBase(result, service.ID)
+}
+
+// SaveDNSZoneDataToPocketBase stores a zone check as a DNS record of the SOA query, with one
+// answer line per nameserver address
+func (ms *MetricsSaver) SaveDNSZoneDataToPocketBase(result *types.OperationResult, serviceID string) {
+	answers := make([]string, 0, len(result.DNSZoneServers))
+	for _, server := range result.DNSZoneServers {
+		if server.Error != "" {
+			answers = append(answers, fmt.Sprintf("%s %s %s", server.Nameserver, server.Address, server.Error))
+			continue
+		}
+		answers = append(answers, fmt.Sprintf("%s %s serial %d", server.Nameserver, server.Address, server.Serial))
+	}
+
 	dnsData := pocketbase.DNSDataRecord{
 		ServiceID:    serviceID,
 		Timestamp:    time.Now(),
@@ -53,22 +113,16 @@ func (ms *MetricsSaver) SaveDNSDataToPocketBase(result *types.OperationResult, s
 		Status:       GetStatusString(result.Success),
 		QueryType:    result.DNSType,
 		ResolveIP:    strings.Join(result.DNSRecords, ","),
-		MsgSize:      fmt.Sprintf("%d", len(result.DNSRecords)),
 		Question:     result.Host,
-		Answer:       strings.Join(result.DNSRecords, ","),
-		Authority:    "", // Not available in current implementation
+		Answer:       strings.Join(answers, "\n"),
+		Server:       result.DNSServer,
 		ErrorMessage: result.Error,
-		Details:      details, // Short, clean message
-		RegionName:   ms.regionName, // Use actual regional info
-		AgentID:      ms.agentID,    // Use actual agent ID
+		Details:      result.Details,
+		RegionName:   ms.regionName,
+		AgentID:      ms.agentID,
 	}
 
 	if err := ms.pbClient.SaveDNSData(dnsData); err != nil {
-		println("Failed to save DNS data to PocketBase:", err.Error())
+		println("Failed to save DNS zone data to PocketBase:", err.Error())
 	}
 }
-
-// Method for monitoring service usage
-func (ms *MetricsSaver) SaveDNSDataForService(service pocketbase.Service, result *types.OperationResult) {
-	ms.SaveDNSDataToPocketBase(result, service.ID)
-}
\ No newline at end of file
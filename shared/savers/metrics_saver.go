@@ -1,16 +1,35 @@
 package savers
 
 import (
+	"sync"
 	"time"
 
 	"service-operation/pocketbase"
 	"service-operation/types"
+	"service-operation/uptime"
 )
 
+// DetailSaver stores the type specific data record for an operation result
+type DetailSaver func(ms *MetricsSaver, result *types.OperationResult, serviceID string)
+
+var (
+	detailSaversMu sync.RWMutex
+	detailSavers   = make(map[types.OperationType]DetailSaver)
+)
+
+// RegisterDetailSaver maps an operation type to the saver for its detailed data.
+// Check types register themselves through the checkers registry.
+func RegisterDetailSaver(opType types.OperationType, saver DetailSaver) {
+	detailSaversMu.Lock()
+	defer detailSaversMu.Unlock()
+	detailSavers[opType] = saver
+}
+
 type MetricsSaver struct {
 	pbClient    *pocketbase.PocketBaseClient
 	regionName  string
 	agentID     string
+	uptime      *uptime.Stats // Rolling uptime of the service, written with the metrics
 }
 
 func NewMetricsSaver(pbClient *pocketbase.PocketBaseClient) *MetricsSaver {
@@ -29,17 +48,22 @@ func NewMetricsSaverWithRegion(pbClient *pocketbase.PocketBaseClient, regionName
 	}
 }
 
+// SetUptime sets the rolling uptime and response time percentiles saved with service metrics
+func (ms *MetricsSaver) SetUptime(stats *uptime.Stats) {
+	ms.uptime = stats
+}
+
 func (ms *MetricsSaver) SaveMetricsToPocketBase(result *types.OperationResult, serviceID string) {
 	// Save general metrics using the new structure
 	metrics := pocketbase.MetricsRecord{
 		ServiceName:  result.Host,
 		Host:         result.Host,
-		Uptime:       0, // This would need to be calculated based on your requirements
+		Uptime:       0, // Ad-hoc operations have no history
 		ResponseTime: result.ResponseTime.Milliseconds(),
 		LastChecked:  time.Now().Format(time.RFC3339),
 		Port:         result.Port,
 		ServiceType:  string(result.Type),
-		Status:       GetStatusString(result.Success),
+		Status:       GetResultStatus(result),
 		ErrorMessage: result.Error,
 		Details:      FormatResultDetails(result),
 		CheckedAt:    time.Now().Format(time.RFC3339),
@@ -52,16 +76,7 @@ func (ms *MetricsSaver) SaveMetricsToPocketBase(result *types.OperationResult, s
 
 	// Save detailed data based on operation type - only once per check
 	if serviceID != "" {
-		switch result.Type {
-		case types.OperationPing:
-			ms.SavePingDataToPocketBase(result, serviceID)
-		case types.OperationHTTP:
-			ms.SaveUptimeDataToPocketBase(result, serviceID)
-		case types.OperationDNS:
-			ms.SaveDNSDataToPocketBase(result, serviceID)
-		case types.OperationTCP:
-			ms.SaveTCPDataToPocketBase(result, serviceID)
-		}
+		ms.saveDetails(result, serviceID)
 	}
 }
 
@@ -71,31 +86,44 @@ func (ms *MetricsSaver) SaveMetricsForService(service pocketbase.Service, result
 	metrics := pocketbase.MetricsRecord{
 		ServiceName:  service.Name,
 		Host:         service.Host,
-		Uptime:       0, // This would need to be calculated based on your requirements
 		ResponseTime: result.ResponseTime.Milliseconds(),
 		LastChecked:  time.Now().Format(time.RFC3339),
 		Port:         service.Port,
+		MaxRetries:   service.MaxRetries,
 		ServiceType:  service.ServiceType,
-		Status:       GetStatusString(result.Success),
+		Status:       GetResultStatus(result),
+		StatusCodes:  service.StatusCodes,
+		Keyword:      service.Keyword,
 		ErrorMessage: result.Error,
 		Details:      FormatResultDetails(result),
 		CheckedAt:    time.Now().Format(time.RFC3339),
 	}
 
+	if ms.uptime != nil {
+		metrics.Uptime = ms.uptime.Day.Uptime
+		metrics.Uptime7d = ms.uptime.Week.Uptime
+		metrics.Uptime30d = ms.uptime.Month.Uptime
+		metrics.ResponseTimeP50 = ms.uptime.Day.P50
+		metrics.ResponseTimeP95 = ms.uptime.Day.P95
+		metrics.ResponseTimeP99 = ms.uptime.Day.P99
+	}
+
 	if err := ms.pbClient.SaveMetrics(metrics); err != nil {
-		// Silent error - no logging to reduce output
-		return
+		// Keep going so the detailed record still gets a chance to be saved or spooled
+		println("Failed to save metrics to PocketBase:", err.Error())
 	}
 
-	// Save detailed data based on service type - only once per service with minimal logging
-	switch service.ServiceType {
-	case "ping", "icmp":
-		ms.SavePingDataToPocketBase(result, service.ID)
-	case "dns":
-		ms.SaveDNSDataToPocketBase(result, service.ID)
-	case "http", "https":
-		ms.SaveUptimeDataToPocketBase(result, service.ID)
-	case "tcp":
-		ms.SaveTCPDataToPocketBase(result, service.ID)
+	// Save detailed data based on the result type - only once per service with minimal logging
+	ms.saveDetails(result, service.ID)
+}
+
+// saveDetails dispatches to the detail saver registered for the result type
+func (ms *MetricsSaver) saveDetails(result *types.OperationResult, serviceID string) {
+	detailSaversMu.RLock()
+	saver, ok := detailSavers[result.Type]
+	detailSaversMu.RUnlock()
+
+	if ok {
+		saver(ms, result, serviceID)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,67 @@
+package savers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-operation/pocketbase"
+	"service-operation/types"
+)
+
+func (ms *MetricsSaver) SaveTLSDataToPocketBase(result *types.OperationResult, serviceID string) {
+	// Create a short, professional status message
+	var details string
+	daysLeft := 0
+	if result.TLSDaysLeft != nil {
+		daysLeft = *result.TLSDaysLeft
+	}
+
+	switch {
+	case result.Success && result.Warning:
+		details = fmt.Sprintf("⚠️ TLS Certificate Expiring - %d days left", daysLeft)
+	case result.Success:
+		details = fmt.Sprintf("✅ TLS Certificate OK - %d days left", daysLeft)
+	case result.TLSNotAfter != nil:
+		details = fmt.Sprintf("❌ TLS Certificate Problem - %s", GetShortErrorMessage(result.Error))
+	default:
+		details = fmt.Sprintf("🔌 TLS Handshake Failed - %s", GetShortErrorMessage(result.Error))
+	}
+
+	if result.TLSVersion != "" {
+		details += fmt.Sprintf(" | %s", result.TLSVersion)
+	}
+
+	tlsData := pocketbase.TLSDataRecord{
+		ServiceID:    serviceID,
+		Timestamp:    time.Now(),
+		ResponseTime: result.ResponseTime.Milliseconds(),
+		Status:       GetResultStatus(result),
+		Host:         result.Host,
+		Port:         strconv.Itoa(result.Port),
+		Subject:      result.TLSSubject,
+		Issuer:       result.TLSIssuer,
+		SANs:         strings.Join(result.TLSSANs, ","),
+		DaysLeft:     daysLeft,
+		TLSVersion:   result.TLSVersion,
+		CipherSuite:  result.TLSCipherSuite,
+		ChainValid:   result.TLSChainValid,
+		ChainError:   result.TLSChainError,
+		ErrorMessage: result.Error,
+		Details:      details,
+		RegionName:   ms.regionName,
+		AgentID:      ms.agentID,
+	}
+
+	if result.TLSNotBefore != nil {
+		tlsData.NotBefore = result.TLSNotBefore.Format(time.RFC3339)
+	}
+	if result.TLSNotAfter != nil {
+		tlsData.NotAfter = result.TLSNotAfter.Format(time.RFC3339)
+	}
+
+	if err := ms.pbClient.SaveTLSData(tlsData); err != nil {
+		println("Failed to save TLS data to PocketBase:", err.Error())
+	}
+}
@@ -24,13 +24,26 @@ func (ms *MetricsSaver) SaveUptimeDataToPocketBase(result *types.OperationResult
 			details += fmt.Sprintf(" | Content: %s", FormatBytes(result.ContentLength))
 		}
 		
+		// Add phase breakdown when the connection was not reused
+		if result.HTTPTCPConnect > 0 {
+			details += fmt.Sprintf(" | DNS: %.0fms, Connect: %.0fms, TLS: %.0fms, TTFB: %.0fms",
+				DurationToMilliseconds(result.HTTPDNSLookup),
+				DurationToMilliseconds(result.HTTPTCPConnect),
+				DurationToMilliseconds(result.HTTPTLSHandshake),
+				DurationToMilliseconds(result.HTTPTimeToFirstByte))
+		}
+
 		// Add server info if available
 		if server, exists := result.HTTPHeaders["Server"]; exists {
 			details += fmt.Sprintf(" | Server: %s", server)
 		}
 	} else {
 		// Error message with status code if available
-		if result.HTTPStatusCode > 0 {
+		if result.FailedAssertion != "" {
+			details = fmt.Sprintf("🧪 HTTP %d Assertion Failed - %s",
+				result.HTTPStatusCode,
+				result.FailedAssertion)
+		} else if result.HTTPStatusCode > 0 {
 			details = fmt.Sprintf("❌ HTTP %d Error - %s", 
 				result.HTTPStatusCode, 
 				GetShortErrorMessage(result.Error))
@@ -47,20 +60,25 @@ func (ms *MetricsSaver) SaveUptimeDataToPocketBase(result *types.OperationResult
 	}
 
 	uptimeData := pocketbase.UptimeDataRecord{
-		ServiceID:    serviceID,
-		Timestamp:    time.Now(),
-		ResponseTime: result.ResponseTime.Milliseconds(),
-		Status:       GetStatusString(result.Success),
-		Packets:      "N/A", // Not applicable for HTTP
-		Latency:      fmt.Sprintf("%.2fms", float64(result.ResponseTime.Nanoseconds())/1000000),
-		StatusCodes:  fmt.Sprintf("%d", result.HTTPStatusCode),
-		Keyword:      "", // Can be populated later if needed
-		ErrorMessage: result.Error,
-		Details:      details, // Short, clean message
-		Region:       ms.regionName, // Use actual regional info
-		RegionID:     ms.agentID,    // Use actual agent ID
-		RegionName:   ms.regionName, // Use actual regional info
-		AgentID:      ms.agentID,    // Use actual agent ID
+		ServiceID:       serviceID,
+		Timestamp:       time.Now(),
+		ResponseTime:    result.ResponseTime.Milliseconds(),
+		Status:          GetStatusString(result.Success),
+		Packets:         "N/A", // Not applicable for HTTP
+		Latency:         fmt.Sprintf("%.2fms", float64(result.ResponseTime.Nanoseconds())/1000000),
+		StatusCodes:     fmt.Sprintf("%d", result.HTTPStatusCode),
+		Keyword:         result.HTTPKeyword,
+		ErrorMessage:    result.Error,
+		Details:         details, // Short, clean message
+		DNSLookup:       DurationToMilliseconds(result.HTTPDNSLookup),
+		TCPConnect:      DurationToMilliseconds(result.HTTPTCPConnect),
+		TLSHandshake:    DurationToMilliseconds(result.HTTPTLSHandshake),
+		TimeToFirstByte: DurationToMilliseconds(result.HTTPTimeToFirstByte),
+		ContentTransfer: DurationToMilliseconds(result.HTTPContentTransfer),
+		Region:          ms.regionName, // Use actual regional info
+		RegionID:        ms.agentID,    // Use actual agent ID
+		RegionName:      ms.regionName, // Use actual regional info
+		AgentID:         ms.agentID,    // Use actual agent ID
 	}
 
 	if err := ms.pbClient.SaveUptimeData(uptimeData); err != nil {
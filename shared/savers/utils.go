@@ -3,7 +3,9 @@ package savers
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"service-operation/types"
 )
@@ -19,6 +21,11 @@ func FormatBytes(bytes int64) string {
 	}
 }
 
+// DurationToMilliseconds converts a duration to milliseconds rounded to 2 decimals
+func DurationToMilliseconds(d time.Duration) float64 {
+	return math.Round(float64(d.Nanoseconds())/10000) / 100
+}
+
 // Helper function to create short error messages
 func GetShortErrorMessage(errorMessage string) string {
 	if errorMessage == "" {
@@ -61,6 +68,14 @@ func GetStatusString(success bool) string {
 	return "down"
 }
 
+// GetResultStatus maps a result to up, warning or down
+func GetResultStatus(result *types.OperationResult) string {
+	if result.Success && result.Warning {
+		return "warning"
+	}
+	return GetStatusString(result.Success)
+}
+
 func FormatResultDetails(result *types.OperationResult) string {
 	// This can be expanded based on operation type
 	if result.Details != "" {
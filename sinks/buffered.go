@@ -0,0 +1,102 @@
+package sinks
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"service-operation/exporter"
+)
+
+// BufferedSink queues results in memory and writes them to the wrapped sink from its own
+// goroutine, retrying failed writes with backoff. Write never blocks; when the queue is full
+// the result is dropped. Writes after Close fail with ErrSinkClosed.
+type BufferedSink struct {
+	sink       ResultSink
+	queue      chan Result
+	maxRetries int
+	retryDelay time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// ErrSinkClosed is returned for results written to a sink that was already closed
+var ErrSinkClosed = errors.New("result sink is closed")
+
+func NewBufferedSink(sink ResultSink, queueSize, maxRetries int, retryDelay time.Duration) *BufferedSink {
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	if retryDelay <= 0 {
+		retryDelay = time.Second
+	}
+
+	b := &BufferedSink{
+		sink:       sink,
+		queue:      make(chan Result, queueSize),
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+		done:       make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *BufferedSink) Name() string {
+	return b.sink.Name()
+}
+
+func (b *BufferedSink) Write(result Result) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		exporter.SinkResultsDropped.Inc()
+		return ErrSinkClosed
+	}
+
+	select {
+	case b.queue <- result:
+	default:
+		exporter.SinkResultsDropped.Inc()
+		log.Printf("⚠️  Result sink %s queue is full, dropping result for %s", b.sink.Name(), result.ServiceName)
+	}
+	return nil
+}
+
+// Close writes the queued results and closes the wrapped sink
+func (b *BufferedSink) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	<-b.done
+	return b.sink.Close()
+}
+
+func (b *BufferedSink) run() {
+	defer close(b.done)
+
+	for result := range b.queue {
+		delay := b.retryDelay
+		for attempt := 0; ; attempt++ {
+			err := b.sink.Write(result)
+			if err == nil {
+				break
+			}
+			if attempt >= b.maxRetries {
+				exporter.SinkResultsDropped.Inc()
+				log.Printf("❌ Result sink %s failed for %s after %d attempts: %v", b.sink.Name(), result.ServiceName, attempt+1, err)
+				break
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
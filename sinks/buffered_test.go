@@ -0,0 +1,80 @@
+package sinks
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink fails the first failures writes and records the rest
+type recordingSink struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	written  []string
+	closed   bool
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(result Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("backend unavailable")
+	}
+	s.written = append(s.written, result.ServiceID)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestBufferedSinkRetriesAndDrainsOnClose(t *testing.T) {
+	inner := &recordingSink{failures: 2}
+	sink := NewBufferedSink(inner, 10, 3, time.Millisecond)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := sink.Write(Result{ServiceID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := inner.written; len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Errorf("written %v, want [a b c]", got)
+	}
+	if inner.attempts != 5 || !inner.closed {
+		t.Errorf("%d attempts, closed %v, want 5 attempts and the inner sink closed", inner.attempts, inner.closed)
+	}
+}
+
+func TestBufferedSinkDropsAfterMaxRetries(t *testing.T) {
+	inner := &recordingSink{failures: 10}
+	sink := NewBufferedSink(inner, 10, 2, time.Millisecond)
+
+	sink.Write(Result{ServiceID: "a"})
+	sink.Close()
+
+	if len(inner.written) != 0 || inner.attempts != 3 {
+		t.Errorf("written %v after %d attempts, want nothing after 3", inner.written, inner.attempts)
+	}
+}
+
+func TestBufferedSinkWriteAfterClose(t *testing.T) {
+	sink := NewBufferedSink(&recordingSink{}, 10, 0, time.Millisecond)
+	sink.Close()
+
+	if err := sink.Write(Result{ServiceID: "late"}); !errors.Is(err, ErrSinkClosed) {
+		t.Errorf("write after close returned %v, want ErrSinkClosed", err)
+	}
+}
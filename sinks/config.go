@@ -0,0 +1,52 @@
+package sinks
+
+import (
+	"time"
+
+	"service-operation/config"
+	"service-operation/pocketbase"
+)
+
+// sinkRetryDelay is the first delay between retries of a failed sink write; it doubles per attempt
+const sinkRetryDelay = time.Second
+
+// NewFromConfig builds the result sinks enabled in the configuration. Each sink gets its own
+// queue and retries, so a slow or failing backend does not hold up the others. pbClient may be
+// nil in standalone mode, where results default to JSON lines on stdout.
+func NewFromConfig(cfg *config.Config, pbClient *pocketbase.PocketBaseClient) (*FanOut, error) {
+	var enabled []ResultSink
+
+	if pbClient != nil {
+		enabled = append(enabled, NewPocketBaseSink(pbClient))
+	}
+
+	if cfg.ResultsOutput != "" || pbClient == nil {
+		jsonlSink, err := NewJSONLSink(cfg.ResultsOutput)
+		if err != nil {
+			return nil, err
+		}
+		enabled = append(enabled, jsonlSink)
+	}
+
+	if cfg.InfluxURL != "" {
+		enabled = append(enabled, NewInfluxSink(cfg.InfluxURL, cfg.InfluxToken))
+	}
+
+	if cfg.StatsDAddr != "" {
+		statsdSink, err := NewStatsDSink(cfg.StatsDAddr, cfg.StatsDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		enabled = append(enabled, statsdSink)
+	}
+
+	if cfg.WebhookURL != "" {
+		enabled = append(enabled, NewWebhookSink(cfg.WebhookURL, cfg.WebhookChangesOnly))
+	}
+
+	buffered := make([]ResultSink, 0, len(enabled))
+	for _, sink := range enabled {
+		buffered = append(buffered, NewBufferedSink(sink, cfg.SinkQueueSize, cfg.SinkMaxRetries, sinkRetryDelay))
+	}
+	return NewFanOut(buffered...), nil
+}
@@ -0,0 +1,55 @@
+package sinks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FanOut writes every result to all of its sinks
+type FanOut struct {
+	sinks []ResultSink
+}
+
+func NewFanOut(sinks ...ResultSink) *FanOut {
+	return &FanOut{sinks: sinks}
+}
+
+func (f *FanOut) Name() string {
+	names := make([]string, 0, len(f.sinks))
+	for _, sink := range f.sinks {
+		names = append(names, sink.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Write passes the result to each sink and reports the sinks that failed
+func (f *FanOut) Write(result Result) error {
+	var failed []string
+	for _, sink := range f.sinks {
+		if err := sink.Write(result); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", sink.Name(), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("result sinks failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (f *FanOut) Close() error {
+	var failed []string
+	for _, sink := range f.sinks {
+		if err := sink.Close(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", sink.Name(), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("closing result sinks failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Len returns the number of sinks
+func (f *FanOut) Len() int {
+	return len(f.sinks)
+}
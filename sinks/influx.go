@@ -0,0 +1,138 @@
+package sinks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InfluxSink writes results as InfluxDB line protocol to a write endpoint, e.g.
+// http://influx:8086/api/v2/write?org=ops&bucket=checks&precision=ns
+type InfluxSink struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func NewInfluxSink(url, token string) *InfluxSink {
+	return &InfluxSink{
+		url:        url,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *InfluxSink) Name() string {
+	return "influx"
+}
+
+func (s *InfluxSink) Write(result Result) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, strings.NewReader(influxLine(result)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx write failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func (s *InfluxSink) Close() error {
+	return nil
+}
+
+// influxLine renders a result as a single regional_check measurement
+func influxLine(result Result) string {
+	var line strings.Builder
+	line.WriteString("regional_check")
+	for _, tag := range [][2]string{
+		{"service_id", result.ServiceID},
+		{"service", result.ServiceName},
+		{"type", result.ServiceType},
+		{"region", result.Region},
+		{"agent", result.Agent},
+	} {
+		if tag[1] != "" {
+			line.WriteString("," + tag[0] + "=" + influxEscapeTag(tag[1]))
+		}
+	}
+
+	fields := []string{
+		"up=" + influxBool(result.ProbeStatus == "up" || result.ProbeStatus == "warning"),
+		"status=" + influxString(result.Status),
+		"response_time_ms=" + strconv.FormatInt(result.ResponseTime, 10) + "i",
+	}
+	if result.Error != "" {
+		fields = append(fields, "error="+influxString(result.Error))
+	}
+	// Type specific fields, sorted so the line is stable
+	extra := resultFields(result.Result)
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := influxValue(extra[name]); ok {
+			fields = append(fields, name+"="+value)
+		}
+	}
+
+	line.WriteString(" " + strings.Join(fields, ","))
+	line.WriteString(" " + strconv.FormatInt(result.Timestamp.UnixNano(), 10) + "\n")
+	return line.String()
+}
+
+// influxValue formats a field value, with integers suffixed by "i"
+func influxValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case string:
+		return influxString(v), true
+	}
+	return "", false
+}
+
+// Line breaks become escaped spaces; a bare space would end the tag set
+var influxTagEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `, "\n", `\ `, "\r", `\ `)
+
+func influxEscapeTag(value string) string {
+	return influxTagEscaper.Replace(value)
+}
+
+var influxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+
+func influxString(value string) string {
+	return `"` + influxStringEscaper.Replace(value) + `"`
+}
+
+func influxBool(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
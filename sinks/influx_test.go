@@ -0,0 +1,135 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+
+	"service-operation/types"
+)
+
+func TestInfluxEscapeTag(t *testing.T) {
+	tests := map[string]string{
+		"api":                `api`,
+		"eu west":            `eu\ west`,
+		"a,b=c":              `a\,b\=c`,
+		"line\nbreak":        `line\ break`,
+		"crlf\r\n":           `crlf\ \ `,
+		`C:\checks`:          `C:\\checks`,
+		`trailing\`:          `trailing\\`,
+		"mixed, \\ = \n end": `mixed\,\ \\\ \=\ \ \ end`,
+	}
+
+	for value, want := range tests {
+		if got := influxEscapeTag(value); got != want {
+			t.Errorf("influxEscapeTag(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestInfluxString(t *testing.T) {
+	tests := map[string]string{
+		"ok":            `"ok"`,
+		`say "hi"`:      `"say \"hi\""`,
+		`C:\path`:       `"C:\\path"`,
+		"first\nsecond": `"first second"`,
+		"":              `""`,
+	}
+
+	for value, want := range tests {
+		if got := influxString(value); got != want {
+			t.Errorf("influxString(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestInfluxValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+		ok    bool
+	}{
+		{value: 42, want: "42i", ok: true},
+		{value: int64(-7), want: "-7i", ok: true},
+		{value: 0.25, want: "0.25", ok: true},
+		{value: true, want: "true", ok: true},
+		{value: "NOERROR", want: `"NOERROR"`, ok: true},
+		{value: []string{"a"}, ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := influxValue(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("influxValue(%v) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInfluxLine(t *testing.T) {
+	const testType types.OperationType = "influx-test"
+	RegisterFields(testType, func(result *types.OperationResult) map[string]interface{} {
+		return map[string]interface{}{"records": len(result.DNSRecords), "rcode": "NOERROR", "ratio": 0.5, "skipped": []int{1}}
+	})
+
+	timestamp := time.Unix(1700000000, 123)
+	base := Result{
+		Timestamp:    timestamp,
+		ServiceID:    "svc1",
+		ServiceName:  "Main API",
+		ServiceType:  "http",
+		Region:       "eu-west",
+		Agent:        "1",
+		Status:       "up",
+		ProbeStatus:  "up",
+		ResponseTime: 120,
+	}
+
+	tests := []struct {
+		name   string
+		result func(Result) Result
+		want   string
+	}{
+		{
+			name:   "success",
+			result: func(r Result) Result { return r },
+			want:   `regional_check,service_id=svc1,service=Main\ API,type=http,region=eu-west,agent=1 up=true,status="up",response_time_ms=120i 1700000000000000123` + "\n",
+		},
+		{
+			name: "failure with error",
+			result: func(r Result) Result {
+				r.Status, r.ProbeStatus, r.Error = "down", "down", "connection \"refused\"\nretry"
+				return r
+			},
+			want: `regional_check,service_id=svc1,service=Main\ API,type=http,region=eu-west,agent=1 up=false,status="down",response_time_ms=120i,error="connection \"refused\" retry" 1700000000000000123` + "\n",
+		},
+		{
+			name: "warning counts as up, empty tags are left out",
+			result: func(r Result) Result {
+				r.ProbeStatus, r.Status, r.Agent = "warning", "warning", ""
+				return r
+			},
+			want: `regional_check,service_id=svc1,service=Main\ API,type=http,region=eu-west up=true,status="warning",response_time_ms=120i 1700000000000000123` + "\n",
+		},
+		{
+			name: "type fields sorted, unsupported values skipped",
+			result: func(r Result) Result {
+				r.Result = &types.OperationResult{Type: testType, DNSRecords: []string{"a", "b"}}
+				return r
+			},
+			want: `regional_check,service_id=svc1,service=Main\ API,type=http,region=eu-west,agent=1 up=true,status="up",response_time_ms=120i,ratio=0.5,rcode="NOERROR",records=2i 1700000000000000123` + "\n",
+		},
+		{
+			name: "unregistered type",
+			result: func(r Result) Result {
+				r.Result = &types.OperationResult{Type: "unknown"}
+				return r
+			},
+			want: `regional_check,service_id=svc1,service=Main\ API,type=http,region=eu-west,agent=1 up=true,status="up",response_time_ms=120i 1700000000000000123` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := influxLine(tt.result(base)); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
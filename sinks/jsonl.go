@@ -0,0 +1,51 @@
+package sinks
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONLSink writes one JSON object per result to a file or stdout
+type JSONLSink struct {
+	name    string
+	mu      sync.Mutex
+	closer  io.Closer
+	encoder *json.Encoder
+}
+
+// NewJSONLSink appends results to path. "-" or "stdout" writes to standard output.
+func NewJSONLSink(path string) (*JSONLSink, error) {
+	if path == "" || path == "-" || path == "stdout" {
+		return &JSONLSink{name: "stdout", encoder: json.NewEncoder(os.Stdout)}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLSink{name: "jsonl:" + path, closer: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (s *JSONLSink) Name() string {
+	return s.name
+}
+
+func (s *JSONLSink) Write(result Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(result)
+}
+
+func (s *JSONLSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
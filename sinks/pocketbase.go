@@ -0,0 +1,40 @@
+package sinks
+
+import (
+	"log"
+
+	"service-operation/pocketbase"
+	"service-operation/shared/savers"
+)
+
+// PocketBaseSink updates the service status and saves the metrics and detail records
+type PocketBaseSink struct {
+	pbClient *pocketbase.PocketBaseClient
+}
+
+func NewPocketBaseSink(pbClient *pocketbase.PocketBaseClient) *PocketBaseSink {
+	return &PocketBaseSink{pbClient: pbClient}
+}
+
+func (s *PocketBaseSink) Name() string {
+	return "pocketbase"
+}
+
+// Write saves the records first, which the client spools while PocketBase is unreachable. A
+// failed status update is not retried since the next check writes the status again.
+func (s *PocketBaseSink) Write(result Result) error {
+	if result.Result != nil {
+		metricsSaver := savers.NewMetricsSaverWithRegion(s.pbClient, result.Region, result.Agent)
+		metricsSaver.SetUptime(result.Uptime)
+		metricsSaver.SaveMetricsForService(result.Service, result.Result)
+	}
+
+	if err := s.pbClient.UpdateServiceStatus(result.ServiceID, result.Status, result.ResponseTime, result.Error); err != nil {
+		log.Printf("Failed to update service status for %s: %v", result.ServiceName, err)
+	}
+	return nil
+}
+
+func (s *PocketBaseSink) Close() error {
+	return nil
+}
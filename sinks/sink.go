@@ -0,0 +1,86 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"service-operation/pocketbase"
+	"service-operation/types"
+	"service-operation/uptime"
+)
+
+// Result is one completed check of a monitored service
+type Result struct {
+	Timestamp     time.Time              `json:"timestamp"`
+	ServiceID     string                 `json:"service_id"`
+	ServiceName   string                 `json:"service_name"`
+	ServiceType   string                 `json:"service_type"`
+	Region        string                 `json:"region"`
+	Agent         string                 `json:"agent"`
+	Status        string                 `json:"status"`         // Confirmed service status
+	ProbeStatus   string                 `json:"probe_status"`   // Outcome of this check alone
+	StatusChanged bool                   `json:"status_changed"` // This check changed the confirmed status
+	ResponseTime  int64                  `json:"response_time"`  // Milliseconds
+	Error         string                 `json:"error,omitempty"`
+	Result        *types.OperationResult `json:"result,omitempty"`
+	Uptime        *uptime.Stats          `json:"uptime,omitempty"` // Rolling uptime, when tracked
+
+	// Service is the full service definition, for sinks that store service fields
+	Service pocketbase.Service `json:"-"`
+}
+
+// ResultSink receives check results, e.g. to write them to a file or forward them to a backend.
+// Write may block; wrap slow sinks with NewBufferedSink so they do not hold up the monitors.
+type ResultSink interface {
+	Name() string
+	Write(result Result) error
+	Close() error
+}
+
+// FieldsFunc returns the type specific fields of a result. Values are ints, floats, bools or strings.
+type FieldsFunc func(result *types.OperationResult) map[string]interface{}
+
+var (
+	fieldsMu sync.RWMutex
+	fieldsFn = make(map[types.OperationType]FieldsFunc)
+)
+
+// RegisterFields maps an operation type to the fields written by time series sinks.
+// Check types register themselves through the checkers registry.
+func RegisterFields(opType types.OperationType, fn FieldsFunc) {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	fieldsFn[opType] = fn
+}
+
+// resultFields returns the type specific fields of a result, or nil for unknown types
+func resultFields(result *types.OperationResult) map[string]interface{} {
+	if result == nil {
+		return nil
+	}
+
+	fieldsMu.RLock()
+	fn, ok := fieldsFn[result.Type]
+	fieldsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return fn(result)
+}
+
+// CloseContext closes a sink, giving up when ctx expires before its queued results are written
+func CloseContext(ctx context.Context, sink ResultSink) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", sink.Name(), ctx.Err())
+	}
+}
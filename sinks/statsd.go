@@ -0,0 +1,65 @@
+package sinks
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// StatsDSink sends results as StatsD metrics over UDP
+type StatsDSink struct {
+	addr   string
+	prefix string
+	conn   net.Conn
+}
+
+func NewStatsDSink(addr, prefix string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsDSink{addr: addr, prefix: strings.TrimSuffix(prefix, "."), conn: conn}, nil
+}
+
+func (s *StatsDSink) Name() string {
+	return "statsd:" + s.addr
+}
+
+func (s *StatsDSink) Write(result Result) error {
+	metric := s.prefix + "." + statsdName(result.ServiceName)
+
+	up := 0
+	if result.ProbeStatus == "up" || result.ProbeStatus == "warning" {
+		up = 1
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s.up:%d|g", metric, up),
+		fmt.Sprintf("%s.response_time:%d|ms", metric, result.ResponseTime),
+		fmt.Sprintf("%s.checks:1|c", s.prefix),
+	}
+	if up == 0 {
+		lines = append(lines, fmt.Sprintf("%s.failures:1|c", metric))
+	}
+
+	// One datagram per result keeps the packet well below common MTUs
+	_, err := s.conn.Write([]byte(strings.Join(lines, "\n")))
+	return err
+}
+
+func (s *StatsDSink) Close() error {
+	return s.conn.Close()
+}
+
+var statsdInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// statsdName turns a service name into a single StatsD metric path segment
+func statsdName(name string) string {
+	name = statsdInvalidChars.ReplaceAllString(strings.ToLower(name), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
@@ -0,0 +1,56 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink posts each result as JSON to a URL
+type WebhookSink struct {
+	url         string
+	changesOnly bool
+	httpClient  *http.Client
+}
+
+// NewWebhookSink creates a webhook sink. With changesOnly set, only results that change the
+// confirmed service status are posted.
+func NewWebhookSink(url string, changesOnly bool) *WebhookSink {
+	return &WebhookSink{
+		url:         url,
+		changesOnly: changesOnly,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Write(result Result) error {
+	if s.changesOnly && !result.StatusChanged {
+		return nil
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *WebhookSink) Close() error {
+	return nil
+}
@@ -0,0 +1,377 @@
+package spool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRejected tells Replay that an entry can never be delivered and should be dropped
+var ErrRejected = errors.New("entry rejected")
+
+// ErrClosed is returned by a replay that was stopped by Close
+var ErrClosed = errors.New("spool closed during replay")
+
+const segmentPrefix = "segment-"
+const segmentSuffix = ".jsonl"
+
+// Entry is a single buffered record write
+type Entry struct {
+	Collection string          `json:"collection"`
+	Data       json.RawMessage `json:"data"`
+	QueuedAt   time.Time       `json:"queued_at"`
+}
+
+// Spool is a durable append-only log of record writes split into segment files.
+// Entries are replayed oldest first; when the total size exceeds the cap the
+// oldest segments are dropped.
+type Spool struct {
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+
+	mu          sync.Mutex
+	current     *os.File
+	currentSeq  int
+	currentSize int64
+	closes      int // Incremented by Close, stops a running replay
+
+	replayMu sync.Mutex
+}
+
+// New opens (or creates) a spool in dir
+func New(dir string, maxBytes, segmentBytes int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory %s: %v", dir, err)
+	}
+	if segmentBytes <= 0 || segmentBytes > maxBytes {
+		segmentBytes = maxBytes
+	}
+
+	s := &Spool{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: segmentBytes,
+	}
+
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	// Never append to a segment left over from a previous run, it may end in a partial line
+	if len(segments) > 0 {
+		s.currentSeq = segments[len(segments)-1].seq
+	}
+
+	return s, nil
+}
+
+// Dir returns the spool directory
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Append buffers a record write for later replay
+func (s *Spool) Append(collection string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(Entry{
+		Collection: collection,
+		Data:       payload,
+		QueuedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil || s.currentSize+int64(len(line)) > s.segmentBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.current.Write(line)
+	s.currentSize += int64(n)
+	if err != nil {
+		return err
+	}
+
+	s.enforceCapLocked()
+	return nil
+}
+
+// Pending returns the number of bytes waiting to be replayed
+func (s *Spool) Pending() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments, err := s.segments()
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, segment := range segments {
+		total += segment.size
+	}
+	return total
+}
+
+// Replay sends buffered entries oldest first. It stops at the first failed send, when ctx is
+// done or when the spool is closed, and keeps the remaining entries for the next replay;
+// entries rejected with ErrRejected are dropped.
+func (s *Spool) Replay(ctx context.Context, send func(Entry) error) (int, error) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	// Seal the active segment so new writes don't interleave with the replay
+	s.mu.Lock()
+	s.closeCurrentLocked()
+	closes := s.closes
+	segments, err := s.segments()
+	s.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, segment := range segments {
+		entries, err := readSegment(segment.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Dropped by the size cap while we were replaying
+			}
+			return replayed, err
+		}
+
+		for i, entry := range entries {
+			if err := s.interrupted(ctx, closes); err != nil {
+				if err := s.rewriteSegment(segment.path, entries[i:]); err != nil {
+					log.Printf("Failed to rewrite spool segment %s: %v", segment.path, err)
+				}
+				return replayed, err
+			}
+			if sendErr := send(entry); sendErr != nil {
+				if errors.Is(sendErr, ErrRejected) {
+					log.Printf("Dropping spooled %s record: %v", entry.Collection, sendErr)
+					continue
+				}
+				if err := s.rewriteSegment(segment.path, entries[i:]); err != nil {
+					log.Printf("Failed to rewrite spool segment %s: %v", segment.path, err)
+				}
+				return replayed, sendErr
+			}
+			replayed++
+		}
+
+		s.mu.Lock()
+		os.Remove(segment.path)
+		s.mu.Unlock()
+	}
+
+	return replayed, nil
+}
+
+// interrupted returns why a replay started before the given number of closes has to stop
+func (s *Spool) interrupted(ctx context.Context, closes int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closes != closes {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Close stops a running replay after its current entry, then syncs and closes the active
+// segment. Later appends open a new segment and later replays run normally.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	s.closes++
+	s.mu.Unlock()
+
+	// Wait until the replay has kept its remaining entries
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil {
+		if err := s.current.Sync(); err != nil {
+			log.Printf("Failed to sync spool segment: %v", err)
+		}
+	}
+	return s.closeCurrentLocked()
+}
+
+type segmentInfo struct {
+	seq  int
+	path string
+	size int64
+}
+
+// segments lists segment files sorted oldest first
+func (s *Spool) segments() ([]segmentInfo, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []segmentInfo
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		var seq int
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), "%d", &seq); err != nil {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, segmentInfo{
+			seq:  seq,
+			path: filepath.Join(s.dir, name),
+			size: info.Size(),
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+	return segments, nil
+}
+
+func (s *Spool) rotateLocked() error {
+	s.closeCurrentLocked()
+
+	s.currentSeq++
+	path := filepath.Join(s.dir, fmt.Sprintf("%s%010d%s", segmentPrefix, s.currentSeq, segmentSuffix))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open spool segment: %v", err)
+	}
+
+	s.current = file
+	s.currentSize = 0
+	return nil
+}
+
+func (s *Spool) closeCurrentLocked() error {
+	if s.current == nil {
+		return nil
+	}
+	err := s.current.Close()
+	s.current = nil
+	s.currentSize = 0
+	return err
+}
+
+// enforceCapLocked drops the oldest segments until the spool fits within maxBytes
+func (s *Spool) enforceCapLocked() {
+	segments, err := s.segments()
+	if err != nil {
+		return
+	}
+
+	var total int64
+	for _, segment := range segments {
+		total += segment.size
+	}
+
+	for _, segment := range segments {
+		if total <= s.maxBytes || segment.seq == s.currentSeq {
+			break
+		}
+		if err := os.Remove(segment.path); err == nil {
+			total -= segment.size
+			log.Printf("⚠️  Spool over %d bytes, dropped oldest segment %s", s.maxBytes, filepath.Base(segment.path))
+		}
+	}
+}
+
+// rewriteSegment atomically replaces a segment with the entries that still need replaying
+func (s *Spool) rewriteSegment(path string, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(path); err != nil {
+		return nil // Dropped by the size cap in the meantime
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// readSegment decodes a segment, skipping a trailing partial line from an interrupted write
+func readSegment(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
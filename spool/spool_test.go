@@ -0,0 +1,218 @@
+package spool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type record struct {
+	N int `json:"n"`
+}
+
+func newTestSpool(t *testing.T, maxBytes, segmentBytes int64) *Spool {
+	t.Helper()
+	s, err := New(t.TempDir(), maxBytes, segmentBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func appendRecords(t *testing.T, s *Spool, from, to int) {
+	t.Helper()
+	for n := from; n <= to; n++ {
+		if err := s.Append("metrics", record{N: n}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// replayAll replays the spool and returns the record numbers in the order they were sent
+func replayAll(t *testing.T, s *Spool) []int {
+	t.Helper()
+	var sent []int
+	if _, err := s.Replay(context.Background(), func(entry Entry) error {
+		sent = append(sent, decode(t, entry))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return sent
+}
+
+func decode(t *testing.T, entry Entry) int {
+	t.Helper()
+	var r record
+	if err := json.Unmarshal(entry.Data, &r); err != nil {
+		t.Fatal(err)
+	}
+	return r.N
+}
+
+func TestAppendReplayInOrder(t *testing.T) {
+	s := newTestSpool(t, 1<<20, 64) // Small segments so the entries span several files
+	appendRecords(t, s, 1, 10)
+
+	if segments, _ := s.segments(); len(segments) < 2 {
+		t.Fatalf("%d segments, want the entries split over several", len(segments))
+	}
+
+	sent := replayAll(t, s)
+	if fmt.Sprint(sent) != fmt.Sprint([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("replayed %v, want 1 to 10 in order", sent)
+	}
+	if pending := s.Pending(); pending != 0 {
+		t.Errorf("%d bytes pending after a full replay", pending)
+	}
+	if sent := replayAll(t, s); len(sent) != 0 {
+		t.Errorf("second replay sent %v again", sent)
+	}
+}
+
+func TestReplayKeepsEntriesAfterFailure(t *testing.T) {
+	s := newTestSpool(t, 1<<20, 1<<20)
+	appendRecords(t, s, 1, 5)
+
+	failure := errors.New("backend down")
+	var sent []int
+	replayed, err := s.Replay(context.Background(), func(entry Entry) error {
+		n := decode(t, entry)
+		if n == 3 {
+			return failure
+		}
+		sent = append(sent, n)
+		return nil
+	})
+	if !errors.Is(err, failure) || replayed != 2 {
+		t.Fatalf("replayed %d with error %v, want 2 and the send error", replayed, err)
+	}
+
+	appendRecords(t, s, 6, 6)
+	if rest := replayAll(t, s); fmt.Sprint(rest) != fmt.Sprint([]int{3, 4, 5, 6}) {
+		t.Errorf("replayed %v after the failure, want [3 4 5 6]", rest)
+	}
+}
+
+func TestReplayDropsRejectedEntries(t *testing.T) {
+	s := newTestSpool(t, 1<<20, 1<<20)
+	appendRecords(t, s, 1, 3)
+
+	replayed, err := s.Replay(context.Background(), func(entry Entry) error {
+		if decode(t, entry) == 2 {
+			return fmt.Errorf("%w: invalid record", ErrRejected)
+		}
+		return nil
+	})
+	if err != nil || replayed != 2 {
+		t.Fatalf("replayed %d with error %v, want 2 and no error", replayed, err)
+	}
+	if pending := s.Pending(); pending != 0 {
+		t.Errorf("%d bytes pending, the rejected entry was kept", pending)
+	}
+}
+
+func TestSizeCapDropsOldestSegments(t *testing.T) {
+	line, _ := json.Marshal(Entry{Collection: "metrics", Data: json.RawMessage(`{"n":10}`)})
+	entrySize := int64(len(line) + 60) // Room for the timestamp
+
+	s := newTestSpool(t, 4*entrySize, entrySize) // One entry per segment, at most four kept
+	appendRecords(t, s, 10, 19)
+
+	if pending := s.Pending(); pending > 4*entrySize {
+		t.Errorf("%d bytes pending, over the %d byte cap", pending, 4*entrySize)
+	}
+
+	sent := replayAll(t, s)
+	if len(sent) == 0 || sent[len(sent)-1] != 19 {
+		t.Fatalf("replayed %v, want the newest entries", sent)
+	}
+	if sent[0] == 10 {
+		t.Errorf("replayed %v, the oldest entries were not dropped", sent)
+	}
+}
+
+func TestReopenSkipsPartialLines(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendRecords(t, s, 1, 2)
+	s.Close()
+
+	// Simulate a crash in the middle of a write
+	segments, _ := s.segments()
+	file, err := os.OpenFile(segments[0].path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"collection":"metrics","data":{"n":`)
+	file.Close()
+
+	reopened, err := New(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	appendRecords(t, reopened, 3, 3)
+
+	files, _ := filepath.Glob(filepath.Join(dir, segmentPrefix+"*"))
+	if len(files) != 2 {
+		t.Errorf("%d segment files, want the new entry in its own segment", len(files))
+	}
+	if sent := replayAll(t, reopened); fmt.Sprint(sent) != fmt.Sprint([]int{1, 2, 3}) {
+		t.Errorf("replayed %v, want [1 2 3]", sent)
+	}
+}
+
+func TestReplayStopsWhenCanceled(t *testing.T) {
+	s := newTestSpool(t, 1<<20, 1<<20)
+	appendRecords(t, s, 1, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replayed, err := s.Replay(ctx, func(entry Entry) error {
+		if decode(t, entry) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || replayed != 2 {
+		t.Fatalf("replayed %d with error %v, want 2 and context.Canceled", replayed, err)
+	}
+	if rest := replayAll(t, s); fmt.Sprint(rest) != fmt.Sprint([]int{3, 4, 5}) {
+		t.Errorf("replayed %v after the cancel, want [3 4 5]", rest)
+	}
+}
+
+func TestCloseStopsRunningReplay(t *testing.T) {
+	s := newTestSpool(t, 1<<20, 1<<20)
+	appendRecords(t, s, 1, 5)
+
+	closed := make(chan struct{})
+	replayed, err := s.Replay(context.Background(), func(entry Entry) error {
+		if decode(t, entry) == 2 {
+			go func() {
+				s.Close()
+				close(closed)
+			}()
+			time.Sleep(20 * time.Millisecond) // Close is waiting for the replay
+		}
+		return nil
+	})
+	if !errors.Is(err, ErrClosed) || replayed != 2 {
+		t.Fatalf("replayed %d with error %v, want 2 and ErrClosed", replayed, err)
+	}
+
+	<-closed
+	if rest := replayAll(t, s); fmt.Sprint(rest) != fmt.Sprint([]int{3, 4, 5}) {
+		t.Errorf("replayed %v after Close, want [3 4 5]", rest)
+	}
+}
@@ -0,0 +1,98 @@
+package statecache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"service-operation/pocketbase"
+)
+
+const (
+	regionalServiceFile  = "regional_service.json"
+	assignedServicesFile = "assigned_services.json"
+)
+
+// Cache persists the last known registration and service assignments of the agent, so
+// monitoring can resume from them after a restart while the backend is unreachable
+type Cache struct {
+	dir string
+}
+
+// cachedServices is the on-disk form of the assigned services list
+type cachedServices struct {
+	SavedAt  time.Time            `json:"saved_at"`
+	Services []pocketbase.Service `json:"services"`
+}
+
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+func (c *Cache) SaveRegionalService(service *pocketbase.RegionalService) error {
+	return c.save(regionalServiceFile, service)
+}
+
+// LoadRegionalService returns the cached regional service, or an error if there is none
+func (c *Cache) LoadRegionalService() (*pocketbase.RegionalService, error) {
+	var service pocketbase.RegionalService
+	if err := c.load(regionalServiceFile, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// SaveServices caches the assigned services without their HTTP credentials, which are only
+// kept by the backend
+func (c *Cache) SaveServices(services []pocketbase.Service) error {
+	stripped := make([]pocketbase.Service, len(services))
+	for i, service := range services {
+		service.BasicAuthPassword = ""
+		service.BearerToken = ""
+		stripped[i] = service
+	}
+	return c.save(assignedServicesFile, cachedServices{SavedAt: time.Now(), Services: stripped})
+}
+
+// LoadServices returns the cached assigned services and when they were saved
+func (c *Cache) LoadServices() ([]pocketbase.Service, time.Time, error) {
+	var cached cachedServices
+	if err := c.load(assignedServicesFile, &cached); err != nil {
+		return nil, time.Time{}, err
+	}
+	return cached.Services, cached.SavedAt, nil
+}
+
+// save writes a file atomically so a crash never leaves a truncated cache behind. The regional
+// service includes the agent token, so the files are only readable by the agent user.
+func (c *Cache) save(name string, v interface{}) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (c *Cache) load(name string, v interface{}) error {
+	path := filepath.Join(c.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid cache file %s: %v", path, err)
+	}
+	return nil
+}
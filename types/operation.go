@@ -10,6 +10,7 @@ const (
 	OperationDNS  OperationType = "dns"
 	OperationTCP  OperationType = "tcp"
 	OperationHTTP OperationType = "http"
+	OperationTLS  OperationType = "tls"
 )
 
 type OperationRequest struct {
@@ -22,6 +23,70 @@ type OperationRequest struct {
 	URL       string        `json:"url,omitempty"`     // For HTTP
 	Method    string        `json:"method,omitempty"`  // For HTTP (GET, POST, etc.)
 	ServiceID string        `json:"service_id,omitempty"` // For linking to specific service
+
+	// TLS certificate thresholds in days left before expiry
+	TLSWarningDays int    `json:"tls_warning_days,omitempty"`
+	TLSDownDays    int    `json:"tls_down_days,omitempty"`
+	ServerName     string `json:"server_name,omitempty"` // SNI override for TLS
+
+	DNSOptions                                              // For DNS nameserver selection and answer validation
+	HTTPOptions                                             // For HTTP request customization
+	Assertions *HTTPAssertions `json:"assertions,omitempty"` // For HTTP response validation
+}
+
+// DNSOptions select the nameserver a DNS check queries and the answers it must return
+type DNSOptions struct {
+	DNSServer        string   `json:"dns_server,omitempty"`         // host, host:port or DoH URL; defaults to the system nameserver
+	DNSProtocol      string   `json:"dns_protocol,omitempty"`       // udp (default), tcp, dot or doh
+	DNSExpected      []string `json:"dns_expected,omitempty"`       // Expected answers of the queried type, e.g. IPs, an MX host or a TXT value
+	DNSExpectedMatch string   `json:"dns_expected_match,omitempty"` // exact (default) or contains
+	DNSSEC           bool     `json:"dnssec,omitempty"`             // Validate the answers with DNSSEC from the root down
+}
+
+// HTTPOptions customize the request sent by an HTTP check
+type HTTPOptions struct {
+	Headers            map[string]string `json:"headers,omitempty"`
+	Body               string            `json:"body,omitempty"`
+	ContentType        string            `json:"content_type,omitempty"`
+	BasicAuthUser      string            `json:"basic_auth_user,omitempty"`
+	BasicAuthPassword  string            `json:"basic_auth_password,omitempty"`
+	BearerToken        string            `json:"bearer_token,omitempty"`
+	NoFollowRedirects  bool              `json:"no_follow_redirects,omitempty"` // Redirects are followed unless set
+	MaxRedirects       int               `json:"max_redirects,omitempty"`       // Defaults to 10
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+}
+
+// HTTPAssertions are the rules an HTTP response must satisfy to count as up
+type HTTPAssertions struct {
+	StatusCodes       string              `json:"status_codes,omitempty"` // e.g. "200,201,300-399" or "2xx"; defaults to 200-399
+	Keywords          []string            `json:"keywords,omitempty"`           // Must all appear in the body
+	ForbiddenKeywords []string            `json:"forbidden_keywords,omitempty"` // Must not appear in the body
+	Regexes           []string            `json:"regexes,omitempty"`            // Must all match the body
+	ForbiddenRegexes  []string            `json:"forbidden_regexes,omitempty"`  // Must not match the body
+	JSONPaths         []JSONPathAssertion `json:"json_paths,omitempty"`
+}
+
+// JSONPathAssertion compares the value at a path such as $.data.items[0].status with an expected value
+type JSONPathAssertion struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+}
+
+// DNSRecord is a resource record of a DNS response
+type DNSRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl"`
+	Value string `json:"value"`
+}
+
+// DNSZoneServer is the SOA answer of one authoritative nameserver address of a zone
+type DNSZoneServer struct {
+	Nameserver   string        `json:"nameserver"`
+	Address      string        `json:"address,omitempty"`
+	Serial       uint32        `json:"serial,omitempty"`
+	ResponseTime time.Duration `json:"response_time"`
+	Error        string        `json:"error,omitempty"` // Why the server is lame or unresponsive
 }
 
 type OperationResult struct {
@@ -43,8 +108,27 @@ type OperationResult struct {
 	RTTs        []time.Duration `json:"rtts,omitempty"`
 	
 	// DNS specific fields
-	DNSRecords  []string        `json:"dns_records,omitempty"`
-	DNSType     string          `json:"dns_type,omitempty"`
+	DNSRecords       []string    `json:"dns_records,omitempty"` // Values of the answer records of the queried type
+	DNSType          string      `json:"dns_type,omitempty"`
+	DNSServer        string      `json:"dns_server,omitempty"`
+	DNSProtocol      string      `json:"dns_protocol,omitempty"`
+	DNSRcode         string      `json:"dns_rcode,omitempty"`
+	DNSAuthoritative bool        `json:"dns_authoritative,omitempty"`
+	DNSMsgSize       int         `json:"dns_msg_size,omitempty"` // Size of the response message in bytes
+	DNSAnswer        []DNSRecord `json:"dns_answer,omitempty"`
+	DNSAuthority     []DNSRecord `json:"dns_authority,omitempty"`
+	DNSAdditional    []DNSRecord `json:"dns_additional,omitempty"`
+
+	// DNSSEC validation: secure, insecure, bogus or indeterminate, with the reason when not secure
+	DNSSECStatus string `json:"dnssec_status,omitempty"`
+	DNSSECReason string `json:"dnssec_reason,omitempty"`
+
+	// Set by the monitor when the answers differ from the previous check of the service
+	DNSAnswerChanged   bool     `json:"dns_answer_changed,omitempty"`
+	DNSPreviousRecords []string `json:"dns_previous_records,omitempty"`
+
+	// DNS zone checks: the SOA answer of every authoritative nameserver
+	DNSZoneServers []DNSZoneServer `json:"dns_zone_servers,omitempty"`
 	
 	// TCP specific fields
 	TCPConnected bool           `json:"tcp_connected,omitempty"`
@@ -55,6 +139,33 @@ type OperationResult struct {
 	HTTPHeaders    map[string]string `json:"http_headers,omitempty"`
 	ContentLength  int64        `json:"content_length,omitempty"`
 	ResponseBody   string       `json:"response_body,omitempty"`
+	HTTPKeyword    string       `json:"http_keyword,omitempty"`
+
+	// HTTP phase timings; time to first byte is measured from the request being written, so it is
+	// server time only. The connection phases are 0 when a connection was reused.
+	HTTPDNSLookup       time.Duration `json:"http_dns_lookup,omitempty"`
+	HTTPTCPConnect      time.Duration `json:"http_tcp_connect,omitempty"`
+	HTTPTLSHandshake    time.Duration `json:"http_tls_handshake,omitempty"`
+	HTTPTimeToFirstByte time.Duration `json:"http_time_to_first_byte,omitempty"`
+	HTTPContentTransfer time.Duration `json:"http_content_transfer,omitempty"`
+	HTTPConnReused      bool          `json:"http_conn_reused,omitempty"`
+
+	FailedAssertion string      `json:"failed_assertion,omitempty"`
+
+	// TLS specific fields
+	TLSVersion     string     `json:"tls_version,omitempty"`
+	TLSCipherSuite string     `json:"tls_cipher_suite,omitempty"`
+	TLSSubject     string     `json:"tls_subject,omitempty"`
+	TLSIssuer      string     `json:"tls_issuer,omitempty"`
+	TLSSANs        []string   `json:"tls_sans,omitempty"`
+	TLSNotBefore   *time.Time `json:"tls_not_before,omitempty"`
+	TLSNotAfter    *time.Time `json:"tls_not_after,omitempty"`
+	TLSDaysLeft    *int       `json:"tls_days_left,omitempty"` // Set whenever a certificate was received, 0 on the day it expires
+	TLSChainValid  bool       `json:"tls_chain_valid,omitempty"`
+	TLSChainError  string     `json:"tls_chain_error,omitempty"`
+
+	// Warning marks a successful check that crossed a warning threshold
+	Warning bool `json:"warning,omitempty"`
 	
 	StartTime   time.Time       `json:"start_time"`
 	EndTime     time.Time       `json:"end_time"`
@@ -0,0 +1,271 @@
+package uptime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// retention is the longest window reported; older hourly buckets are dropped
+	retention = 30 * 24 * time.Hour
+
+	// latencyGrowth is the width ratio of consecutive response time histogram bins, so
+	// percentiles are accurate to about 5%
+	latencyGrowth = 1.1
+)
+
+// Window is the uptime and response time summary of a service over a period
+type Window struct {
+	Checks int     `json:"checks"`
+	Uptime float64 `json:"uptime"` // Percentage of checks that were up or warning
+	P50    int64   `json:"p50"`    // Response time percentiles of successful checks, in milliseconds
+	P95    int64   `json:"p95"`
+	P99    int64   `json:"p99"`
+}
+
+// Stats summarizes a service over the rolling windows
+type Stats struct {
+	ServiceID   string `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	Day         Window `json:"24h"`
+	Week        Window `json:"7d"`
+	Month       Window `json:"30d"`
+}
+
+// hourBucket aggregates the checks of one service during one hour
+type hourBucket struct {
+	Hour    int64          `json:"hour"` // Unix time of the start of the hour
+	Checks  int            `json:"checks"`
+	Up      int            `json:"up"`
+	Latency map[int]uint32 `json:"latency,omitempty"` // Histogram bin -> successful checks
+}
+
+type serviceHistory struct {
+	Name    string        `json:"name"`
+	Buckets []*hourBucket `json:"buckets"` // Oldest first
+}
+
+// Tracker keeps hourly check aggregates per service for the last 30 days and persists them
+// to a file so the windows survive restarts
+type Tracker struct {
+	path string
+
+	mu       sync.Mutex
+	services map[string]*serviceHistory
+	dirty    bool
+}
+
+// New creates a tracker persisted at path, loading the history saved by a previous run.
+// An unreadable file is ignored and the history starts empty.
+func New(path string) (*Tracker, error) {
+	t := &Tracker{
+		path:     path,
+		services: make(map[string]*serviceHistory),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, &t.services); err != nil {
+		t.services = make(map[string]*serviceHistory)
+		return t, fmt.Errorf("invalid uptime history %s: %v", path, err)
+	}
+
+	cutoff := time.Now().Add(-retention)
+	for id, history := range t.services {
+		if history == nil || !history.prune(cutoff) {
+			delete(t.services, id)
+		}
+	}
+	return t, nil
+}
+
+// Record adds a check result and returns the updated stats of the service
+func (t *Tracker) Record(serviceID, serviceName string, at time.Time, up bool, responseTime time.Duration) Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	history, ok := t.services[serviceID]
+	if !ok {
+		history = &serviceHistory{}
+		t.services[serviceID] = history
+	}
+	history.Name = serviceName
+
+	hour := at.Truncate(time.Hour).Unix()
+	var bucket *hourBucket
+	if n := len(history.Buckets); n > 0 && history.Buckets[n-1].Hour == hour {
+		bucket = history.Buckets[n-1]
+	} else {
+		bucket = &hourBucket{Hour: hour}
+		history.Buckets = append(history.Buckets, bucket)
+		history.prune(at.Add(-retention))
+	}
+
+	bucket.Checks++
+	if up {
+		bucket.Up++
+		if bucket.Latency == nil {
+			bucket.Latency = make(map[int]uint32)
+		}
+		bucket.Latency[latencyBin(responseTime)]++
+	}
+	t.dirty = true
+
+	return history.stats(serviceID, at)
+}
+
+// Get returns the stats of a service
+func (t *Tracker) Get(serviceID string) (Stats, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	history, ok := t.services[serviceID]
+	if !ok {
+		return Stats{}, false
+	}
+	return history.stats(serviceID, time.Now()), true
+}
+
+// All returns the stats of every tracked service, sorted by service name
+func (t *Tracker) All() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	all := make([]Stats, 0, len(t.services))
+	for id, history := range t.services {
+		all = append(all, history.stats(id, now))
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].ServiceName != all[j].ServiceName {
+			return all[i].ServiceName < all[j].ServiceName
+		}
+		return all[i].ServiceID < all[j].ServiceID
+	})
+	return all
+}
+
+// Save writes the history to disk if it changed since the last save
+func (t *Tracker) Save() error {
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(t.services)
+	t.dirty = false
+	t.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o750); err != nil {
+		return err
+	}
+
+	// Write atomically so a crash never leaves a truncated file behind
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.path)
+}
+
+// prune drops buckets older than cutoff and reports whether any are left
+func (h *serviceHistory) prune(cutoff time.Time) bool {
+	first := 0
+	for first < len(h.Buckets) && h.Buckets[first].Hour < cutoff.Truncate(time.Hour).Unix() {
+		first++
+	}
+	if first > 0 {
+		h.Buckets = append([]*hourBucket(nil), h.Buckets[first:]...)
+	}
+	return len(h.Buckets) > 0
+}
+
+func (h *serviceHistory) stats(serviceID string, now time.Time) Stats {
+	return Stats{
+		ServiceID:   serviceID,
+		ServiceName: h.Name,
+		Day:         h.window(now, 24*time.Hour),
+		Week:        h.window(now, 7*24*time.Hour),
+		Month:       h.window(now, retention),
+	}
+}
+
+// window summarizes the hourly buckets that overlap the last period, including the current hour
+func (h *serviceHistory) window(now time.Time, period time.Duration) Window {
+	since := now.Add(-period).Truncate(time.Hour).Add(time.Hour).Unix()
+
+	var w Window
+	up := 0
+	histogram := make(map[int]uint32)
+	var successes uint32
+	for _, bucket := range h.Buckets {
+		if bucket.Hour < since {
+			continue
+		}
+		w.Checks += bucket.Checks
+		up += bucket.Up
+		for bin, count := range bucket.Latency {
+			histogram[bin] += count
+			successes += count
+		}
+	}
+
+	if w.Checks > 0 {
+		w.Uptime = math.Round(float64(up)/float64(w.Checks)*100*1000) / 1000
+	}
+	if successes > 0 {
+		bins := make([]int, 0, len(histogram))
+		for bin := range histogram {
+			bins = append(bins, bin)
+		}
+		sort.Ints(bins)
+
+		w.P50 = percentile(bins, histogram, successes, 0.50)
+		w.P95 = percentile(bins, histogram, successes, 0.95)
+		w.P99 = percentile(bins, histogram, successes, 0.99)
+	}
+	return w
+}
+
+// percentile returns the response time at rank p of a histogram with sorted bins
+func percentile(bins []int, histogram map[int]uint32, total uint32, p float64) int64 {
+	rank := uint32(math.Ceil(p * float64(total)))
+	var seen uint32
+	for _, bin := range bins {
+		seen += histogram[bin]
+		if seen >= rank {
+			return binValue(bin)
+		}
+	}
+	return binValue(bins[len(bins)-1])
+}
+
+// latencyBin maps a response time to its logarithmic histogram bin
+func latencyBin(d time.Duration) int {
+	ms := float64(d) / float64(time.Millisecond)
+	if ms < 0 {
+		ms = 0
+	}
+	return int(math.Log1p(ms) / math.Log(latencyGrowth))
+}
+
+// binValue returns the midpoint of a histogram bin in milliseconds
+func binValue(bin int) int64 {
+	lower := math.Expm1(float64(bin) * math.Log(latencyGrowth))
+	upper := math.Expm1(float64(bin+1) * math.Log(latencyGrowth))
+	return int64(math.Round((lower + upper) / 2))
+}
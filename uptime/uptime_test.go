@@ -0,0 +1,142 @@
+package uptime
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLatencyBins(t *testing.T) {
+	for _, ms := range []int64{1, 7, 50, 120, 999, 4500, 30000} {
+		got := binValue(latencyBin(time.Duration(ms) * time.Millisecond))
+		if diff := math.Abs(float64(got - ms)); diff > float64(ms)*0.05+1 {
+			t.Errorf("%dms falls in a bin valued %dms", ms, got)
+		}
+	}
+	if bin := latencyBin(-time.Second); bin != 0 {
+		t.Errorf("negative response time in bin %d, want 0", bin)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	tracker := &Tracker{services: make(map[string]*serviceHistory)}
+
+	checks := []struct {
+		age time.Duration
+		up  bool
+	}{
+		{age: 31 * 24 * time.Hour, up: true}, // Beyond the retention
+		{age: 20 * 24 * time.Hour, up: false},
+		{age: 3 * 24 * time.Hour, up: false},
+		{age: 3 * 24 * time.Hour, up: true},
+		{age: 23*time.Hour + 45*time.Minute, up: false}, // Its hour started more than 24 hours ago
+		{age: 2 * time.Hour, up: true},
+		{age: 2 * time.Hour, up: true},
+		{age: time.Hour, up: true},
+	}
+	for _, check := range checks {
+		tracker.Record("svc1", "API", now.Add(-check.age), check.up, 100*time.Millisecond)
+	}
+	stats := tracker.Record("svc1", "API", now, true, 100*time.Millisecond)
+
+	tests := []struct {
+		name   string
+		window Window
+		checks int
+		uptime float64
+	}{
+		{name: "24h", window: stats.Day, checks: 4, uptime: 100},
+		{name: "7d", window: stats.Week, checks: 7, uptime: 71.429},
+		{name: "30d", window: stats.Month, checks: 8, uptime: 62.5},
+	}
+	for _, tt := range tests {
+		if tt.window.Checks != tt.checks || tt.window.Uptime != tt.uptime {
+			t.Errorf("%s window: %d checks, %v%% up, want %d checks, %v%% up", tt.name, tt.window.Checks, tt.window.Uptime, tt.checks, tt.uptime)
+		}
+	}
+
+	if first := tracker.services["svc1"].Buckets[0].Hour; first < now.Add(-retention).Unix() {
+		t.Errorf("bucket of %v kept beyond the retention", time.Unix(first, 0))
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := &Tracker{services: make(map[string]*serviceHistory)}
+
+	record := func(n int, up bool, responseTime time.Duration) {
+		for i := 0; i < n; i++ {
+			tracker.Record("svc1", "API", now, up, responseTime)
+		}
+	}
+	record(88, true, 100*time.Millisecond)
+	record(9, true, time.Second)
+	record(3, true, 5*time.Second)
+	record(49, false, 10*time.Second) // Failed checks are left out of the percentiles
+
+	window := tracker.Record("svc1", "API", now, false, 10*time.Second).Month
+	for _, p := range []struct {
+		name      string
+		got, want int64
+	}{
+		{name: "p50", got: window.P50, want: 100},
+		{name: "p95", got: window.P95, want: 1000},
+		{name: "p99", got: window.P99, want: 5000},
+	} {
+		if diff := math.Abs(float64(p.got - p.want)); diff > float64(p.want)*0.05 {
+			t.Errorf("%s = %dms, want about %dms", p.name, p.got, p.want)
+		}
+	}
+	if window.Checks != 150 || window.Uptime != 66.667 {
+		t.Errorf("%d checks, %v%% up, want 150 checks, 66.667%% up", window.Checks, window.Uptime)
+	}
+}
+
+func TestNoSuccessfulChecks(t *testing.T) {
+	tracker := &Tracker{services: make(map[string]*serviceHistory)}
+	stats := tracker.Record("svc1", "API", time.Now(), false, time.Second)
+	if stats.Day != (Window{Checks: 1}) {
+		t.Errorf("window %+v, want one check, no uptime and no percentiles", stats.Day)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "uptime.json")
+	tracker, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tracker.Record("svc2", "Web", now, true, 80*time.Millisecond)
+	tracker.Record("svc1", "API", now, false, 0)
+	tracker.Record("svc1", "API", now, true, 120*time.Millisecond)
+	if err := tracker.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := loaded.All()
+	if len(all) != 2 || all[0].ServiceName != "API" || all[1].ServiceName != "Web" {
+		t.Fatalf("loaded %+v, want API and Web sorted by name", all)
+	}
+	if all[0].Day.Checks != 2 || all[0].Day.Uptime != 50 {
+		t.Errorf("loaded API window %+v, want 2 checks and 50%% up", all[0].Day)
+	}
+	if _, ok := loaded.Get("missing"); ok {
+		t.Error("stats returned for an unknown service")
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if corrupt, err := New(path); err == nil || len(corrupt.All()) != 0 {
+		t.Errorf("corrupt history loaded with error %v and %d services", err, len(corrupt.All()))
+	}
+}